@@ -0,0 +1,239 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"container/heap"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GitBranch is a branch of a repository compared to the default branch.
+type GitBranch struct {
+	Ref    GitRef
+	Commit *GitCommit // last commit, nil if unknown
+
+	Default bool // whether this is the default branch
+	Ahead   int  // number of commits not in the default branch
+	Behind  int  // number of commits of the default branch missing in the branch
+
+	Issues []*Issue // issues mentioned in the branch name, if their project uses the repository
+}
+
+// Name returns the short name of the branch.
+func (b *GitBranch) Name() string {
+	return b.Ref.Name()
+}
+
+// Age returns the time passed since the last commit of the branch.
+func (b *GitBranch) Age() time.Duration {
+	if b.Commit == nil {
+		return 0
+	}
+	return time.Since(b.Commit.CommitTime)
+}
+
+// IsStale reports whether the last commit of the branch is older than
+// maxAge.
+func (b *GitBranch) IsStale(maxAge time.Duration) bool {
+	return b.Commit != nil && b.Age() > maxAge
+}
+
+// IsMerged reports whether all commits of the branch are part of the
+// default branch.
+func (b *GitBranch) IsMerged() bool {
+	return b.Ahead == 0
+}
+
+// Branches returns all branches of the repository, sorted by the time of
+// their last commit, newest first.
+func (r *GitRepo) Branches() []*GitBranch {
+	defaultBranch := r.DefaultBranch()
+	defaultHead := r.DefaultHead()
+	defaultCommits := r.mergedCommits()
+	var ret []*GitBranch
+	for _, ref := range r.refs {
+		if !ref.IsBranch() {
+			continue
+		}
+		b := &GitBranch{
+			Ref:     ref,
+			Commit:  ref.Commit(),
+			Default: ref.Name() == defaultBranch,
+		}
+		b.Ahead, b.Behind = r.aheadBehind(ref.Sha1, defaultHead, defaultCommits)
+		seen := newSet()
+		for _, key := range issueKeyRx.FindAllString(strings.ToUpper(ref.Name()), -1) {
+			i, ok := r.c.issuesByKey[key]
+			if ok && !seen.has(key) && (i.p == nil || i.p.hasRepo(r)) {
+				seen.put(key)
+				b.Issues = append(b.Issues, i)
+			}
+		}
+		ret = append(ret, b)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i].commitTime(), ret[j].commitTime()
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return ret[i].Ref.Ref < ret[j].Ref.Ref
+	})
+	return ret
+}
+
+// BranchCount returns the number of branches of the repository.
+func (r *GitRepo) BranchCount() int {
+	n := 0
+	for _, ref := range r.refs {
+		if ref.IsBranch() {
+			n++
+		}
+	}
+	return n
+}
+
+// aheadBehind returns the number of commits reachable from head, but not
+// from defaultHead, and vice versa. defaultCommits are the ancestors of
+// defaultHead.
+//
+// Both walks stop where the histories meet, so their cost depends on how
+// far the branch diverged, not on the size of the history. Like git
+// rev-list --count, the walk of the default branch relies on commit times
+// to find where it meets the branch.
+func (r *GitRepo) aheadBehind(head, defaultHead string, defaultCommits map[string]struct{}) (ahead, behind int) {
+	// commits of the branch which are not in the default branch, and the
+	// commits of the default branch where the walk stopped:
+	var merged []string
+	seen := newSet()
+	queue := []string{head}
+	if head == "" {
+		queue = nil
+	}
+	for len(queue) > 0 {
+		sha1 := queue[0]
+		queue = queue[1:]
+		if seen.has(sha1) {
+			continue
+		}
+		seen.put(sha1)
+		if _, ok := defaultCommits[sha1]; ok {
+			merged = append(merged, sha1)
+			continue
+		}
+		ahead++
+		if gc := r.commits[sha1]; gc != nil {
+			queue = append(queue, gc.Parents...)
+		}
+	}
+
+	// Walk the default branch and the merged commits newest first. Commits
+	// reached by the merged ones are part of the branch. The walk ends once
+	// only those are left.
+	flags := make(map[string]int)
+	q := &commitQueue{r: r}
+	push := func(sha1 string, f int) {
+		if flags[sha1]|f != flags[sha1] {
+			flags[sha1] |= f
+			heap.Push(q, sha1)
+		}
+	}
+	if defaultHead != "" {
+		push(defaultHead, fromDefault)
+	}
+	for _, sha1 := range merged {
+		push(sha1, fromBranch)
+	}
+	done := newSet()
+	for q.hasDefaultOnly(flags) {
+		sha1 := heap.Pop(q).(string)
+		if done.has(sha1) {
+			continue
+		}
+		done.put(sha1)
+		f := flags[sha1]
+		if f == fromDefault {
+			behind++
+		}
+		if gc := r.commits[sha1]; gc != nil {
+			for _, parent := range gc.Parents {
+				push(parent, f)
+			}
+		}
+	}
+	return ahead, behind
+}
+
+// Flags of the commits walked by aheadBehind.
+const (
+	fromDefault = 1 << iota // reachable from the default head
+	fromBranch              // reachable from the head of the branch
+)
+
+// commitQueue is a heap of commits, newest first.
+type commitQueue struct {
+	r     *GitRepo
+	sha1s []string
+}
+
+func (q *commitQueue) Len() int { return len(q.sha1s) }
+func (q *commitQueue) Less(i, j int) bool {
+	return q.r.commitTime(q.sha1s[i]).After(q.r.commitTime(q.sha1s[j]))
+}
+func (q *commitQueue) Swap(i, j int)      { q.sha1s[i], q.sha1s[j] = q.sha1s[j], q.sha1s[i] }
+func (q *commitQueue) Push(x interface{}) { q.sha1s = append(q.sha1s, x.(string)) }
+func (q *commitQueue) Pop() interface{} {
+	sha1 := q.sha1s[len(q.sha1s)-1]
+	q.sha1s = q.sha1s[:len(q.sha1s)-1]
+	return sha1
+}
+
+// hasDefaultOnly reports whether a queued commit is not known to be part
+// of the branch.
+func (q *commitQueue) hasDefaultOnly(flags map[string]int) bool {
+	for _, sha1 := range q.sha1s {
+		if flags[sha1] == fromDefault {
+			return true
+		}
+	}
+	return false
+}
+
+// commitTime returns the commit time of the commit, or the zero time if
+// the commit is not known.
+func (r *GitRepo) commitTime(sha1 string) time.Time {
+	if gc := r.commits[sha1]; gc != nil {
+		return gc.CommitTime
+	}
+	return time.Time{}
+}
+
+func (b *GitBranch) commitTime() time.Time {
+	if b.Commit == nil {
+		return time.Time{}
+	}
+	return b.Commit.CommitTime
+}
+
+// DefaultBranch returns the name of the default branch. This is master, if
+// it points to the same commit as HEAD, or any other branch pointing to
+// HEAD. DefaultBranch returns "" if no branch points to HEAD.
+func (r *GitRepo) DefaultBranch() string {
+	head := r.DefaultHead()
+	var name string
+	for _, ref := range r.refs {
+		if !ref.IsBranch() || ref.Sha1 != head {
+			continue
+		}
+		if ref.Name() == "master" {
+			return "master"
+		}
+		if name == "" || ref.Name() < name {
+			name = ref.Name()
+		}
+	}
+	return name
+}
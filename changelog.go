@@ -0,0 +1,184 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Name returns the short name of the repository, which is the last element
+// of its URL without the ".git" suffix.
+func (r *GitRepo) Name() string {
+	return strings.TrimSuffix(path.Base(strings.TrimRight(r.URL, "/")), ".git")
+}
+
+// GitRepo returns the repository with the given URL or name, or nil if no
+// such repository exists.
+func (c *Corpus) GitRepo(name string) *GitRepo {
+	if r, ok := c.GitRepos[name]; ok {
+		return r
+	}
+	for _, r := range c.GitRepos {
+		if r.Name() == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// Resolve returns the sha1 of the named ref. The name is either a full ref
+// name, a tag or branch name, or a (possibly abbreviated) commit sha1.
+// Resolve returns "" if the name cannot be resolved.
+func (r *GitRepo) Resolve(name string) string {
+	for _, full := range []string{name, "refs/tags/" + name, "refs/heads/" + name} {
+		for _, ref := range r.refs {
+			if ref.Ref == full {
+				return ref.Sha1
+			}
+		}
+	}
+	if len(name) < 4 {
+		return ""
+	}
+	var match string
+	for sha1 := range r.commits {
+		if strings.HasPrefix(sha1, name) {
+			if match != "" {
+				return "" // ambiguous
+			}
+			match = sha1
+		}
+	}
+	return match
+}
+
+// Issues returns the known issues mentioned in the commit message whose
+// project contains the commit.
+func (gc *GitCommit) Issues() []*Issue {
+	var ret []*Issue
+	seen := newSet()
+	for _, key := range issueKeyRx.FindAllString(gc.Msg, -1) {
+		i, ok := gc.r.c.issuesByKey[key]
+		if !ok || seen.has(key) || !i.acceptsCommit(gc) {
+			continue
+		}
+		seen.put(key)
+		ret = append(ret, i)
+	}
+	return ret
+}
+
+// NumStat returns the total number of added and deleted lines of the
+// commit.
+func (gc *GitCommit) NumStat() (added, deleted int64) {
+	for _, f := range gc.DiffTree {
+		added += f.added
+		deleted += f.deleted
+	}
+	return added, deleted
+}
+
+// Changelog lists the commits reachable from one ref but not from another.
+type Changelog struct {
+	Repo *GitRepo
+	From string // ref name or sha1 of the excluded commits
+	To   string // ref name or sha1 of the included commits
+
+	Commits []*GitCommit // newest first
+	Issues  []*Issue     // issues linked to any of the commits, sorted by key
+	Authors []GitPerson  // sorted by name
+
+	Files   int // number of changed files
+	Added   int64
+	Deleted int64
+}
+
+// Changelog returns the commits reachable from to, but not from from. An
+// empty from lists all commits reachable from to.
+func (r *GitRepo) Changelog(from, to string) (*Changelog, error) {
+	toSha1 := r.Resolve(to)
+	if toSha1 == "" {
+		return nil, fmt.Errorf("unknown ref %q", to)
+	}
+	var fromSha1 string
+	if from != "" {
+		fromSha1 = r.Resolve(from)
+		if fromSha1 == "" {
+			return nil, fmt.Errorf("unknown ref %q", from)
+		}
+	}
+
+	excluded := r.ancestors(fromSha1)
+	cl := &Changelog{Repo: r, From: from, To: to}
+	issues := make(map[string]*Issue)
+	authors := make(map[string]GitPerson)
+	files := newSet()
+	for sha1 := range r.ancestors(toSha1) {
+		if _, ok := excluded[sha1]; ok {
+			continue
+		}
+		gc := r.commits[sha1]
+		if gc == nil {
+			continue
+		}
+		cl.Commits = append(cl.Commits, gc)
+		for _, i := range gc.Issues() {
+			issues[i.ID] = i
+		}
+		authors[gc.Author.Str] = gc.Author
+		for file := range gc.DiffTree {
+			files.put(file)
+		}
+		added, deleted := gc.NumStat()
+		cl.Added += added
+		cl.Deleted += deleted
+	}
+	cl.Files = len(files.m)
+
+	sort.Slice(cl.Commits, func(i, j int) bool {
+		a, b := cl.Commits[i], cl.Commits[j]
+		if !a.CommitTime.Equal(b.CommitTime) {
+			return a.CommitTime.After(b.CommitTime)
+		}
+		return a.Sha1 < b.Sha1
+	})
+	for _, i := range issues {
+		cl.Issues = append(cl.Issues, i)
+	}
+	sort.Slice(cl.Issues, func(i, j int) bool { return cl.Issues[i].IssueKey < cl.Issues[j].IssueKey })
+	for _, p := range authors {
+		cl.Authors = append(cl.Authors, p)
+	}
+	sort.Slice(cl.Authors, func(i, j int) bool { return cl.Authors[i].Str < cl.Authors[j].Str })
+	return cl, nil
+}
+
+// ancestors returns the sha1 of all commits reachable from head, including
+// head itself.
+func (r *GitRepo) ancestors(head string) map[string]struct{} {
+	seen := make(map[string]struct{})
+	if head == "" {
+		return seen
+	}
+	seen[head] = struct{}{}
+	queue := []string{head}
+	for len(queue) > 0 {
+		gc := r.commits[queue[0]]
+		queue = queue[1:]
+		if gc == nil {
+			continue
+		}
+		for _, parent := range gc.Parents {
+			if _, ok := seen[parent]; !ok {
+				seen[parent] = struct{}{}
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,203 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/urld/devdashboard"
+)
+
+// releaseCalendar is a Gantt-style overview of all releases.
+type releaseCalendar struct {
+	Start, End time.Time
+	Today      float64 // position of today in percent, or -1 if out of range
+	Ticks      []calendarTick
+	Upcoming   []calendarRow
+	Past       []calendarRow
+}
+
+type calendarTick struct {
+	Label  string
+	Offset float64 // percent
+}
+
+// calendarRow is a release with the position of its freeze period on the
+// calendar.
+type calendarRow struct {
+	*devdashboard.Release
+	Offset float64 // percent
+	Width  float64 // percent
+}
+
+func newReleaseCalendar(releases []*devdashboard.Release, now time.Time) *releaseCalendar {
+	devdashboard.SortReleases(releases)
+	cal := &releaseCalendar{Today: -1}
+	for _, r := range releases {
+		for _, t := range []time.Time{r.FreezeDate, r.ReleaseDate} {
+			if t.IsZero() {
+				continue
+			}
+			if cal.Start.IsZero() || t.Before(cal.Start) {
+				cal.Start = t
+			}
+			if t.After(cal.End) {
+				cal.End = t
+			}
+		}
+	}
+	// leave some room before the first freeze and after the last release:
+	cal.Start = cal.Start.AddDate(0, 0, -7)
+	cal.End = cal.End.AddDate(0, 0, 7)
+
+	if now.After(cal.Start) && now.Before(cal.End) {
+		cal.Today = cal.offset(now)
+	}
+	cal.Ticks = cal.ticks()
+	for _, r := range releases {
+		row := calendarRow{Release: r}
+		freeze, release := r.FreezeDate, r.ReleaseDate
+		if freeze.IsZero() {
+			freeze = release
+		}
+		if release.IsZero() {
+			release = freeze
+		}
+		if !freeze.IsZero() {
+			row.Offset = cal.offset(freeze)
+			row.Width = cal.offset(release) - row.Offset
+		}
+		if row.Width < 1 {
+			row.Width = 1
+		}
+		if r.Status() == devdashboard.ReleaseReleased || r.Status() == devdashboard.ReleaseClosed {
+			cal.Past = append(cal.Past, row)
+		} else {
+			cal.Upcoming = append(cal.Upcoming, row)
+		}
+	}
+	// most recent releases first:
+	for i, j := 0, len(cal.Past)-1; i < j; i, j = i+1, j-1 {
+		cal.Past[i], cal.Past[j] = cal.Past[j], cal.Past[i]
+	}
+	return cal
+}
+
+// offset returns the position of t on the calendar in percent.
+func (cal *releaseCalendar) offset(t time.Time) float64 {
+	total := cal.End.Sub(cal.Start)
+	if total <= 0 {
+		return 0
+	}
+	return float64(t.Sub(cal.Start)) / float64(total) * 100
+}
+
+// ticks returns a tick for every month, or for every year if the calendar
+// spans more than two years.
+func (cal *releaseCalendar) ticks() []calendarTick {
+	var ticks []calendarTick
+	yearly := cal.End.Sub(cal.Start) > 2*365*24*time.Hour
+	t := time.Date(cal.Start.Year(), cal.Start.Month(), 1, 0, 0, 0, 0, cal.Start.Location())
+	for t.Before(cal.End) {
+		next := t.AddDate(0, 1, 0)
+		label := t.Format("Jan 2006")
+		if yearly {
+			t = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+			next = t.AddDate(1, 0, 0)
+			label = t.Format("2006")
+		}
+		if t.After(cal.Start) {
+			ticks = append(ticks, calendarTick{Label: label, Offset: cal.offset(t)})
+		}
+		t = next
+	}
+	return ticks
+}
+
+func calendarHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="releases.ics"`)
+	writeICS(w, corpus.Releases, time.Now())
+}
+
+// writeICS writes an iCalendar feed with all-day events for the freeze and
+// release dates of all releases.
+func writeICS(w io.Writer, releases map[string]*devdashboard.Release, now time.Time) {
+	ics := icsWriter{w: w}
+	ics.line("BEGIN:VCALENDAR")
+	ics.line("VERSION:2.0")
+	ics.line("PRODID:-//urld//devdashboard//EN")
+	ics.line("CALSCALE:GREGORIAN")
+	ics.line("X-WR-CALNAME:Releases")
+	sorted := make([]*devdashboard.Release, 0, len(releases))
+	for _, r := range releases {
+		sorted = append(sorted, r)
+	}
+	devdashboard.SortReleases(sorted)
+	for _, r := range sorted {
+		if !r.FreezeDate.IsZero() {
+			ics.event(r.ID+"-freeze", "Code freeze: "+r.Name, r.Description, r.FreezeDate, now)
+		}
+		if !r.ReleaseDate.IsZero() {
+			ics.event(r.ID+"-release", "Release: "+r.Name, r.Description, r.ReleaseDate, now)
+		}
+	}
+	ics.line("END:VCALENDAR")
+}
+
+type icsWriter struct {
+	w io.Writer
+}
+
+func (ics icsWriter) event(uid, summary, description string, date, now time.Time) {
+	const dateFmt = "20060102"
+	ics.line("BEGIN:VEVENT")
+	ics.line("UID:" + icsEscape(uid) + "@devdashboard")
+	ics.line("DTSTAMP:" + now.UTC().Format("20060102T150405Z"))
+	ics.line("DTSTART;VALUE=DATE:" + date.Format(dateFmt))
+	ics.line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format(dateFmt))
+	ics.line("SUMMARY:" + icsEscape(summary))
+	if description != "" {
+		ics.line("DESCRIPTION:" + icsEscape(description))
+	}
+	ics.line("END:VEVENT")
+}
+
+// line writes a content line, folded after 75 octets as required by
+// RFC 5545.
+func (ics icsWriter) line(s string) {
+	max := 75
+	for len(s) > max {
+		i := max
+		for i > 0 && !isRuneStart(s[i]) {
+			i--
+		}
+		fmt.Fprintf(ics.w, "%s\r\n ", s[:i])
+		s = s[i:]
+		max = 74 // continuation lines start with a space
+	}
+	fmt.Fprintf(ics.w, "%s\r\n", s)
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/urld/devdashboard/devdashconfig"
+)
+
+var config = devdashconfig.Default()
+
+// initConfig loads the configuration file given by the -config flag.
+// Server options given as flags override the configuration file.
+func initConfig() {
+	if *configPath == "" {
+		return
+	}
+	cfg, err := devdashconfig.Load(*configPath)
+	if err != nil {
+		log.Fatalf("unable to load configuration:\n%v", err)
+	}
+	config = cfg
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, opt := range []struct {
+		flag string
+		val  *string
+		cfg  string
+	}{
+		{"http", httpAddr, cfg.Server.HTTP},
+		{"base", basePath, cfg.Server.Base},
+		{"data", dataPath, cfg.Server.Data},
+		{"aliases", aliasPath, cfg.Server.Aliases},
+		{"upstream", upstream, cfg.Server.Upstream},
+	} {
+		if !set[opt.flag] && opt.cfg != "" {
+			*opt.val = opt.cfg
+		}
+	}
+}
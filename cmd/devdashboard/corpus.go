@@ -9,14 +9,20 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/urld/devdashboard"
 	"github.com/urld/devdashboard/devdashdata"
+	"github.com/urld/devdashboard/devdashmetrics"
 )
 
 var (
-	corpus *devdashboard.Corpus
+	corpus  *devdashboard.Corpus
+	aliases *devdashboard.Aliases
 )
 
 func initCorpus() {
@@ -24,12 +30,47 @@ func initCorpus() {
 	if targetDir == "" {
 		targetDir = devdashdata.DefaultDir()
 	}
+	if *upstream != "" {
+		replicateCorpus(*upstream, targetDir)
+		return
+	}
 	log.Printf("initializing corpus from %s...", targetDir)
-	c, err := devdashdata.Get(context.Background(), targetDir)
-	if err != nil {
+	if err := os.MkdirAll(targetDir, 0700); err != nil {
+		log.Fatalf("unable to initialize corpus: %v", err)
+	}
+	// syncers and corpus share the logger, which serializes writing and
+	// reading the log.
+	logger := config.NewLogger(targetDir)
+	c := new(devdashboard.Corpus)
+	c.SetReleaseTagPattern(config.UI.ReleaseTagPattern)
+	if err := c.Initialize(context.Background(), logger); err != nil {
 		log.Fatalf("unable to initialize corpus: %v", err)
 	}
 	corpus = c
+
+	s := config.NewScheduler(targetDir)
+	if s == nil {
+		followLog(c, config.Sync.Interval.Duration)
+		return
+	}
+	// only one process may sync into the log
+	if err := logger.Acquire(); err != nil {
+		log.Fatalf("unable to sync: %v", err)
+	}
+	s.Logger = logger
+	scheduler = s
+	runScheduler(s, c)
+}
+
+func initAliases() {
+	if *aliasPath == "" {
+		return
+	}
+	a, err := devdashboard.LoadAliases(*aliasPath)
+	if err != nil {
+		log.Fatalf("unable to load aliases: %v", err)
+	}
+	aliases = a
 }
 
 func checkReady(w http.ResponseWriter) bool {
@@ -40,21 +81,67 @@ func checkReady(w http.ResponseWriter) bool {
 	return true
 }
 
+// releasePage is a release with its issues optionally filtered by label.
+type releasePage struct {
+	*devdashboard.Release
+	Label string
+}
+
+// Issues returns the issues of the milestone, which match the label filter.
+func (p releasePage) Issues(m *devdashboard.Milestone) []*devdashboard.Issue {
+	return filterIssues(m.Issues, p.Label)
+}
+
+// filterIssues returns the issues with the given label sorted by issue key.
+// All issues are returned if label is empty.
+func filterIssues(issues map[string]*devdashboard.Issue, label string) []*devdashboard.Issue {
+	ret := make([]*devdashboard.Issue, 0, len(issues))
+	for _, i := range issues {
+		if label == "" || i.HasLabel(label) {
+			ret = append(ret, i)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].IssueKey < ret[j].IssueKey })
+	return ret
+}
+
 func releaseHandler(w http.ResponseWriter, r *http.Request) {
 	if !checkReady(w) {
 		return
 	}
 	name := strings.TrimPrefix(r.URL.Path, "/release/")
+	label := r.FormValue("label")
 
 	corpus.RLock()
 	defer corpus.RUnlock()
 
-	data := make([]*devdashboard.Release, 0)
+	if strings.HasSuffix(name, "/notes") {
+		notesHandler(w, r, strings.TrimSuffix(name, "/notes"))
+		return
+	}
+	if name == "" {
+		releases := make([]*devdashboard.Release, 0, len(corpus.Releases))
+		for _, release := range corpus.Releases {
+			releases = append(releases, release)
+		}
+		err := renderHTML(w, "releases", []*releaseCalendar{newReleaseCalendar(releases, time.Now())})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	data := make([]releasePage, 0)
 	for _, release := range corpus.Releases {
-		if name == "" || release.Name == name {
-			data = append(data, release)
+		if release.Name == name {
+			data = append(data, releasePage{Release: release, Label: label})
 		}
 	}
+	if len(data) == 0 {
+		http.NotFound(w, r)
+		return
+	}
 
 	err := renderHTML(w, "release", data)
 	if err != nil {
@@ -63,3 +150,206 @@ func releaseHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 }
+
+// board is a kanban board of a milestone's issues.
+type board struct {
+	Milestone *devdashboard.Milestone
+	Label     string
+	Columns   []boardColumn
+}
+
+type boardColumn struct {
+	Category devdashboard.StatusCategory
+	Issues   []*devdashboard.Issue
+}
+
+func newBoard(m *devdashboard.Milestone, label string) *board {
+	b := &board{Milestone: m, Label: label}
+	issues := filterIssues(m.Issues, label)
+	for _, sc := range devdashboard.StatusCategories {
+		col := boardColumn{Category: sc}
+		for _, i := range issues {
+			if i.StatusCategory() == sc {
+				col.Issues = append(col.Issues, i)
+			}
+		}
+		b.Columns = append(b.Columns, col)
+	}
+	return b
+}
+
+func milestoneHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/milestone/")
+	if !strings.HasSuffix(path, "/board") {
+		http.NotFound(w, r)
+		return
+	}
+	id := strings.TrimSuffix(path, "/board")
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	m, ok := corpus.Milestones[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	err := renderHTML(w, "board", []*board{newBoard(m, r.FormValue("label"))})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func sprintHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/sprint/")
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	var err error
+	if id == "" {
+		data := make([]*devdashboard.Sprint, 0, len(corpus.Sprints))
+		for _, s := range corpus.Sprints {
+			data = append(data, s)
+		}
+		devdashboard.SortSprints(data)
+		// most recent sprints first:
+		for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+			data[i], data[j] = data[j], data[i]
+		}
+		err = renderHTML(w, "sprints", [][]*devdashboard.Sprint{data})
+	} else {
+		s, ok := corpus.Sprints[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		err = renderHTML(w, "sprint", []*devdashboard.Sprint{s})
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func userHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/user/")
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	var err error
+	if id == "" {
+		data := make([]*devdashboard.UserActivity, 0, len(corpus.TrackerUsers))
+		for _, u := range corpus.TrackerUsers {
+			data = append(data, corpus.UserActivity(u, aliases))
+		}
+		sort.Slice(data, func(i, j int) bool { return data[i].User.Name < data[j].User.Name })
+		err = renderHTML(w, "users", [][]*devdashboard.UserActivity{data})
+	} else {
+		u, ok := corpus.TrackerUsers[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		err = renderHTML(w, "user", []*devdashboard.UserActivity{corpus.UserActivity(u, aliases)})
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// searchPage is the result of an issue search.
+type searchPage struct {
+	Query  string
+	Issues []*devdashboard.Issue
+}
+
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+	q := r.FormValue("q")
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	data := searchPage{Query: q, Issues: corpus.SearchIssues(devdashboard.ParseQuery(q))}
+	err := renderHTML(w, "search", []searchPage{data})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+	window, err := parseWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	data := devdashmetrics.Compute(corpus, window)
+
+	if r.FormValue("format") == "json" {
+		err = renderJSON(w, data)
+	} else {
+		err = renderHTML(w, "metrics", []*devdashmetrics.Report{data})
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// parseWindow parses the time window of a request. The window is either
+// given by the number of days until now ("days"), or by a start and end
+// date ("since" and "until"). It defaults to the last days configured by
+// ui.metricsDays.
+func parseWindow(r *http.Request) (devdashmetrics.Window, error) {
+	const dateFmt = "2006-01-02"
+	window := devdashmetrics.LastDays(config.UI.MetricsDays)
+	if s := r.FormValue("days"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil {
+			return window, errors.New("invalid number of days: " + s)
+		}
+		if days < 1 {
+			return window, errors.New("days must be at least 1")
+		}
+		window = devdashmetrics.LastDays(days)
+	}
+	if s := r.FormValue("since"); s != "" {
+		t, err := time.Parse(dateFmt, s)
+		if err != nil {
+			return window, errors.New("invalid since date: " + s)
+		}
+		window.Start = t
+	}
+	if s := r.FormValue("until"); s != "" {
+		t, err := time.Parse(dateFmt, s)
+		if err != nil {
+			return window, errors.New("invalid until date: " + s)
+		}
+		window.End = t
+	}
+	return window, nil
+}
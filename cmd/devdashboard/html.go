@@ -5,16 +5,20 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"html/template"
 	"io"
 	"log"
+	"net/http"
 	"path/filepath"
 	"sync"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fsnotify/fsnotify"
+	"github.com/urld/devdashboard"
 )
 
 var (
@@ -28,9 +32,26 @@ func initTemplates(basePath string, failOnErr bool) {
 	}
 
 	rootTmpl := filepath.Join(basePath, "templates/root.tmpl")
+	issueTmpl := filepath.Join(basePath, "templates/issue.tmpl")
 
 	for name, contentTmpl := range map[string]string{
-		"release": "release.tmpl",
+		"release":   "release.tmpl",
+		"releases":  "releases.tmpl",
+		"metrics":   "metrics.tmpl",
+		"users":     "users.tmpl",
+		"user":      "user.tmpl",
+		"board":     "board.tmpl",
+		"search":    "search.tmpl",
+		"notes":     "notes.tmpl",
+		"changelog": "changelog.tmpl",
+		"repos":     "repos.tmpl",
+		"branches":  "branches.tmpl",
+		"churn":     "churn.tmpl",
+		"projects":  "projects.tmpl",
+		"project":   "project.tmpl",
+		"status":    "status.tmpl",
+		"sprints":   "sprints.tmpl",
+		"sprint":    "sprint.tmpl",
 	} {
 		contentTmpl = filepath.Join(basePath, "templates", contentTmpl)
 
@@ -39,8 +60,11 @@ func initTemplates(basePath string, failOnErr bool) {
 			"fmtDate":     fmtDate,
 			"fmtDateTime": fmtDateTime,
 			"fmtRelTime":  fmtRelTime,
+			"fmtDuration": fmtDuration,
+			"storyPoints": devdashboard.StoryPoints,
+			"percent":     percent,
 		})
-		tmpl, err := tmpl.ParseFiles(rootTmpl, contentTmpl)
+		tmpl, err := tmpl.ParseFiles(rootTmpl, issueTmpl, contentTmpl)
 		if err != nil {
 			if failOnErr {
 				log.Fatal(err)
@@ -91,6 +115,21 @@ func renderHTML(w io.Writer, name string, data interface{}) error {
 	return tmpl.ExecuteTemplate(w, "root", data)
 }
 
+func renderJSON(w http.ResponseWriter, data interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+// percent returns n relative to max in percent.
+func percent(n, max int64) float64 {
+	if max == 0 {
+		return 0
+	}
+	return float64(n) / float64(max) * 100
+}
+
 func fmtDate(t time.Time) string {
 	return t.Format("2006-01-02")
 }
@@ -103,3 +142,13 @@ func fmtDateTime(t time.Time) string {
 func fmtRelTime(t time.Time) string {
 	return humanize.Time(t)
 }
+
+func fmtDuration(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%.1fh", d.Hours())
+	}
+	return fmt.Sprintf("%.1fd", d.Hours()/24)
+}
@@ -28,13 +28,17 @@ You may use the -base flag to specify an alternate location.
 `
 
 var (
-	httpAddr = flag.String("http", "127.0.0.1:8080", "HTTP Service address (e.g., '127.0.0.1:8080')")
-	basePath = flag.String("base", "", "base path for html templates and static resources")
-	dataPath = flag.String("data", "", "data path ")
+	httpAddr   = flag.String("http", "127.0.0.1:8080", "HTTP Service address (e.g., '127.0.0.1:8080')")
+	basePath   = flag.String("base", "", "base path for html templates and static resources")
+	dataPath   = flag.String("data", "", "data path ")
+	aliasPath  = flag.String("aliases", "", "file mapping issue tracker users to additional git emails")
+	configPath = flag.String("config", "", "configuration file, see package devdashconfig")
+	upstream   = flag.String("upstream", "", "URL of a log server to replicate the data from, such as devdashsync -http")
 )
 
 func main() {
 	flag.Parse()
+	initConfig()
 
 	if *basePath == "" {
 		p, err := build.Default.Import(basePkg, "", build.FindOnly)
@@ -46,6 +50,7 @@ func main() {
 		*basePath = p.Dir
 	}
 
+	initAliases()
 	initServer()
 	go initCorpus()
 
@@ -58,6 +63,15 @@ func initServer() {
 
 	http.HandleFunc("/static/", fileServer(*basePath))
 	http.HandleFunc("/release/", releaseHandler)
+	http.HandleFunc("/metrics/", metricsHandler)
+	http.HandleFunc("/user/", userHandler)
+	http.HandleFunc("/milestone/", milestoneHandler)
+	http.HandleFunc("/project/", projectHandler)
+	http.HandleFunc("/sprint/", sprintHandler)
+	http.HandleFunc("/releases.ics", calendarHandler)
+	http.HandleFunc("/search/", searchHandler)
+	http.HandleFunc("/repo/", repoHandler)
+	http.HandleFunc("/status", statusHandler)
 	http.HandleFunc("/corpusviz/", corpusvizHandler)
 }
 
@@ -0,0 +1,121 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/urld/devdashboard"
+)
+
+// notesPage is the release notes of a release with its rendering options.
+type notesPage struct {
+	*devdashboard.ReleaseNotes
+	GroupBy string
+	Commits bool // include commit summaries
+}
+
+// notesHandler serves the release notes of the named release as html,
+// markdown (format=md) or plain text (format=text).
+// The caller must hold the corpus read lock.
+func notesHandler(w http.ResponseWriter, r *http.Request, name string) {
+	var release *devdashboard.Release
+	for _, rel := range corpus.Releases {
+		if rel.Name == name {
+			release = rel
+			break
+		}
+	}
+	if release == nil {
+		http.NotFound(w, r)
+		return
+	}
+	groupBy := r.FormValue("group")
+	if groupBy != devdashboard.GroupByLabel {
+		groupBy = devdashboard.GroupByType
+	}
+	data := notesPage{
+		ReleaseNotes: release.Notes(groupBy),
+		GroupBy:      groupBy,
+		Commits:      r.FormValue("commits") != "",
+	}
+
+	var err error
+	switch r.FormValue("format") {
+	case "md", "markdown":
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		err = writeNotes(w, data, markdownNotes)
+	case "text", "txt":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		err = writeNotes(w, data, textNotes)
+	default:
+		err = renderHTML(w, "notes", []notesPage{data})
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// notesStyle defines the markup of a textual release notes format.
+type notesStyle struct {
+	title   func(string) string
+	project func(string) string
+	section func(string) string
+	issue   string // format of an issue line with key and title
+	commit  string // format of a commit line with short sha1 and summary
+}
+
+var markdownNotes = notesStyle{
+	title:   func(s string) string { return "# " + s + "\n" },
+	project: func(s string) string { return "## " + s + "\n" },
+	section: func(s string) string { return "### " + s + "\n" },
+	issue:   "* **%s** %s\n",
+	commit:  "  * `%s` %s\n",
+}
+
+var textNotes = notesStyle{
+	title:   func(s string) string { return s + "\n" + strings.Repeat("=", len(s)) + "\n" },
+	project: func(s string) string { return s + "\n" + strings.Repeat("-", len(s)) + "\n" },
+	section: func(s string) string { return s + ":\n" },
+	issue:   "  - %s %s\n",
+	commit:  "      %s %s\n",
+}
+
+func writeNotes(w io.Writer, data notesPage, style notesStyle) error {
+	var b strings.Builder
+	b.WriteString(style.title("Release Notes: " + data.Release.Name))
+	if data.Release.Description != "" {
+		b.WriteString("\n" + data.Release.Description + "\n")
+	}
+	for _, p := range data.Projects {
+		b.WriteString("\n" + style.project(p.Name()))
+		for _, s := range p.Sections {
+			b.WriteString("\n" + style.section(s.Title) + "\n")
+			for _, i := range s.Issues {
+				fmt.Fprintf(&b, style.issue, i.IssueKey, i.Title)
+				if !data.Commits {
+					continue
+				}
+				for _, gc := range i.CommitList() {
+					fmt.Fprintf(&b, style.commit, shortSha1(gc.Sha1), gc.Summary())
+				}
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func shortSha1(sha1 string) string {
+	if len(sha1) > 7 {
+		return sha1[:7]
+	}
+	return sha1
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/urld/devdashboard"
+)
+
+// projectPage is a project with its most recent commits.
+type projectPage struct {
+	*devdashboard.Project
+	Commits      []*devdashboard.GitCommit
+	TotalCommits int
+}
+
+const projectPageCommits = 20
+
+func projectHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/project/")
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	var err error
+	if id == "" {
+		data := make([]*devdashboard.Project, 0, len(corpus.Projects))
+		for _, p := range corpus.Projects {
+			data = append(data, p)
+		}
+		sort.Slice(data, func(i, j int) bool { return data[i].Name < data[j].Name })
+		err = renderHTML(w, "projects", [][]*devdashboard.Project{data})
+	} else {
+		p, ok := corpus.Projects[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		data := projectPage{Project: p, Commits: p.Commits()}
+		data.TotalCommits = len(data.Commits)
+		if len(data.Commits) > projectPageCommits {
+			data.Commits = data.Commits[:projectPageCommits]
+		}
+		err = renderHTML(w, "project", []projectPage{data})
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urld/devdashboard"
+	"github.com/urld/devdashboard/devdashmetrics"
+)
+
+func repoHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkReady(w) {
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/repo/")
+
+	corpus.RLock()
+	defer corpus.RUnlock()
+
+	switch {
+	case path == "":
+		reposHandler(w, r)
+	case strings.HasSuffix(path, "/changelog"):
+		changelogHandler(w, r, strings.TrimSuffix(path, "/changelog"))
+	case strings.HasSuffix(path, "/churn"):
+		churnHandler(w, r, strings.TrimSuffix(path, "/churn"))
+	default:
+		branchesHandler(w, r, path)
+	}
+}
+
+// reposHandler lists all repositories.
+// The caller must hold the corpus read lock.
+func reposHandler(w http.ResponseWriter, r *http.Request) {
+	data := make([]*devdashboard.GitRepo, 0, len(corpus.GitRepos))
+	for _, repo := range corpus.GitRepos {
+		data = append(data, repo)
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].Name() < data[j].Name() })
+	err := renderHTML(w, "repos", [][]*devdashboard.GitRepo{data})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// branchesPage lists the branches of a repository.
+type branchesPage struct {
+	Repo       *devdashboard.GitRepo
+	Branches   []*devdashboard.GitBranch
+	StaleAfter time.Duration
+}
+
+// StaleDays returns the number of days after which a branch is stale.
+func (p branchesPage) StaleDays() int {
+	return int(p.StaleAfter.Hours() / 24)
+}
+
+// branchesHandler lists the branches of the named repository. Branches
+// without commits for "stale" days (ui.staleBranchDays by default) are
+// flagged as stale.
+// The caller must hold the corpus read lock.
+func branchesHandler(w http.ResponseWriter, r *http.Request, name string) {
+	repo := corpus.GitRepo(name)
+	if repo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	staleDays := config.UI.StaleBranchDays
+	if s := r.FormValue("stale"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil {
+			http.Error(w, "invalid number of days: "+s, http.StatusBadRequest)
+			return
+		}
+		if days < 1 {
+			http.Error(w, "stale must be at least 1", http.StatusBadRequest)
+			return
+		}
+		staleDays = days
+	}
+	data := branchesPage{
+		Repo:       repo,
+		Branches:   repo.Branches(),
+		StaleAfter: time.Duration(staleDays) * 24 * time.Hour,
+	}
+	err := renderHTML(w, "branches", []branchesPage{data})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// changelogJSON is the JSON representation of a changelog.
+type changelogJSON struct {
+	Repo    string            `json:"repo"`
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Commits []changelogCommit `json:"commits"`
+	Issues  []changelogIssue  `json:"issues"`
+	Authors []string          `json:"authors"`
+	Files   int               `json:"files"`
+	Added   int64             `json:"added"`
+	Deleted int64             `json:"deleted"`
+}
+
+type changelogCommit struct {
+	Sha1    string    `json:"sha1"`
+	Summary string    `json:"summary"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+	Issues  []string  `json:"issues,omitempty"`
+	Added   int64     `json:"added"`
+	Deleted int64     `json:"deleted"`
+}
+
+type changelogIssue struct {
+	Key    string `json:"key"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Closed bool   `json:"closed"`
+	URL    string `json:"url,omitempty"`
+}
+
+func newChangelogJSON(cl *devdashboard.Changelog) *changelogJSON {
+	data := &changelogJSON{
+		Repo:    cl.Repo.URL,
+		From:    cl.From,
+		To:      cl.To,
+		Files:   cl.Files,
+		Added:   cl.Added,
+		Deleted: cl.Deleted,
+	}
+	for _, gc := range cl.Commits {
+		c := changelogCommit{
+			Sha1:    gc.Sha1,
+			Summary: gc.Summary(),
+			Author:  gc.Author.Str,
+			Time:    gc.CommitTime,
+		}
+		for _, i := range gc.Issues() {
+			c.Issues = append(c.Issues, i.IssueKey)
+		}
+		c.Added, c.Deleted = gc.NumStat()
+		data.Commits = append(data.Commits, c)
+	}
+	for _, i := range cl.Issues {
+		data.Issues = append(data.Issues, changelogIssue{
+			Key:    i.IssueKey,
+			Title:  i.Title,
+			Status: i.Status,
+			Closed: i.Closed,
+			URL:    i.URL,
+		})
+	}
+	for _, p := range cl.Authors {
+		data.Authors = append(data.Authors, p.Str)
+	}
+	return data
+}
+
+// changelogHandler serves the commits reachable from the ref "to" (HEAD by
+// default), but not from the ref "from".
+// The caller must hold the corpus read lock.
+func changelogHandler(w http.ResponseWriter, r *http.Request, name string) {
+	repo := corpus.GitRepo(name)
+	if repo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	to := r.FormValue("to")
+	if to == "" {
+		to = "HEAD"
+	}
+	cl, err := repo.Changelog(r.FormValue("from"), to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.FormValue("format") == "json" {
+		err = renderJSON(w, newChangelogJSON(cl))
+	} else {
+		err = renderHTML(w, "changelog", []*devdashboard.Changelog{cl})
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// churnPage is a churn report with at most Limit hotspots and issues.
+type churnPage struct {
+	*devdashmetrics.ChurnReport
+	RepoName string
+	Depth    int
+	Limit    int
+}
+
+// TopHotspots returns at most Limit hotspots.
+func (p churnPage) TopHotspots() []*devdashmetrics.Hotspot {
+	if len(p.Hotspots) > p.Limit {
+		return p.Hotspots[:p.Limit]
+	}
+	return p.Hotspots
+}
+
+// TopIssues returns at most Limit issues.
+func (p churnPage) TopIssues() []*devdashmetrics.IssueChurn {
+	if len(p.Issues) > p.Limit {
+		return p.Issues[:p.Limit]
+	}
+	return p.Issues
+}
+
+// MaxPeriodLines returns the maximum number of changed lines of all
+// periods, which is used to scale the churn chart.
+func (p churnPage) MaxPeriodLines() int64 {
+	var max int64
+	for _, period := range p.Periods {
+		if period.Lines() > max {
+			max = period.Lines()
+		}
+	}
+	return max
+}
+
+// churnHandler serves the code churn of the named repository within the
+// requested window, optionally restricted to the files of a "project".
+// Directories are aggregated up to "depth" elements (1 by default) and "n"
+// hotspots are listed (ui.hotspots by default).
+// The caller must hold the corpus read lock.
+func churnHandler(w http.ResponseWriter, r *http.Request, name string) {
+	repo := corpus.GitRepo(name)
+	if repo == nil {
+		http.NotFound(w, r)
+		return
+	}
+	window, err := parseWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	depth, limit := 1, config.UI.Hotspots
+	for param, v := range map[string]*int{"depth": &depth, "n": &limit} {
+		if s := r.FormValue(param); s != "" {
+			*v, err = strconv.Atoi(s)
+			if err != nil {
+				http.Error(w, "invalid "+param+": "+s, http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if depth < 1 {
+		http.Error(w, "depth must be at least 1", http.StatusBadRequest)
+		return
+	}
+	if limit < 0 {
+		http.Error(w, "n must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	data := churnPage{
+		RepoName: repo.Name(),
+		Depth:    depth,
+		Limit:    limit,
+	}
+	if id := r.FormValue("project"); id != "" {
+		p, ok := corpus.Projects[id]
+		if !ok {
+			http.Error(w, "unknown project: "+id, http.StatusBadRequest)
+			return
+		}
+		data.ChurnReport = devdashmetrics.ComputeProjectChurn(p, repo, window, depth)
+	} else {
+		data.ChurnReport = devdashmetrics.ComputeChurn(repo, window, depth)
+	}
+	if r.FormValue("format") == "json" {
+		err = renderJSON(w, data.ChurnReport)
+	} else {
+		err = renderHTML(w, "churn", []churnPage{data})
+	}
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/urld/devdashboard"
+	"github.com/urld/devdashboard/devdashdata"
+)
+
+// scheduler runs the configured syncers, nil if none are configured.
+var scheduler *devdashboard.Scheduler
+
+// runScheduler runs the scheduler and updates the corpus after each sync.
+func runScheduler(s *devdashboard.Scheduler, c *devdashboard.Corpus) {
+	s.Corpus = c
+	s.Synced = c.Update
+	s.Run(context.Background())
+}
+
+// followLog updates the corpus every interval, which picks up the
+// mutations logged by other processes, such as devdashsync.
+func followLog(c *devdashboard.Corpus, interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := c.Update(context.Background()); err != nil {
+			log.Printf("unable to update corpus: %v", err)
+		}
+	}
+}
+
+// replicateCorpus initializes the corpus from the logs of a log server,
+// which are cached in dir, and keeps it up-to-date.
+func replicateCorpus(server, dir string) {
+	log.Printf("replicating corpus from %s into %s...", server, dir)
+	c, err := devdashdata.GetFromServer(context.Background(), server, dir)
+	if err != nil {
+		log.Fatalf("unable to initialize corpus: %v", err)
+	}
+	c.SetReleaseTagPattern(config.UI.ReleaseTagPattern)
+	corpus = c
+	for {
+		// Update waits for new mutations on the server.
+		if err := c.Update(context.Background()); err != nil {
+			log.Printf("unable to update corpus: %v", err)
+			time.Sleep(config.Sync.Interval.Duration)
+		}
+	}
+}
+
+func statusHandler(w http.ResponseWriter, r *http.Request) {
+	var data []devdashboard.SyncStatus
+	if scheduler != nil {
+		data = scheduler.Status()
+	}
+	err := renderHTML(w, "status", [][]devdashboard.SyncStatus{data})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -7,7 +7,9 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
@@ -25,16 +27,34 @@ var (
 func main() {
 	flag.Parse()
 	dir := targetDir()
-	os.RemoveAll(dir)
 	os.MkdirAll(dir, 0700)
+	logger = devdashboard.NewDiskMutationLogger(dir)
+	if err := logger.Acquire(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer logger.Release()
+	clearDir(dir)
 
 	fmt.Println("logging fixtures to selected dir...", dir)
-	logger = devdashboard.NewDiskMutationLogger(dir)
 
 	log(&devdashpb.Mutation{
 		Project: &devdashpb.ProjectMutation{
 			Id:   "ABC",
 			Name: "Alpha Bravo Charlie",
+			Repos: []*devdashpb.ProjectRepo{
+				{Repo: "https://github.com/urld/abc.git"},
+			},
+			Labels: []*devdashpb.Label{
+				{Id: "abc-bug", Name: "bug", Color: "e11d21", Description: "Something isn't working"},
+				{Id: "abc-docs", Name: "documentation", Color: "0075ca", Description: "Improvements or additions to documentation"},
+			},
+			Workflow: &devdashpb.Workflow{Statuses: []*devdashpb.WorkflowStatus{
+				{Name: "New", Category: devdashpb.StatusCategory_TODO},
+				{Name: "In Progress", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Review", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Done", Category: devdashpb.StatusCategory_DONE},
+			}},
 			Milestones: []*devdashpb.TrackerMilestone{
 				{
 					Id:          "abc201902",
@@ -55,6 +75,16 @@ func main() {
 		Project: &devdashpb.ProjectMutation{
 			Id:   "DEF",
 			Name: "Another project",
+			Labels: []*devdashpb.Label{
+				{Id: "def-bug", Name: "bug", Color: "e11d21", Description: "Something isn't working"},
+				{Id: "def-enhancement", Name: "enhancement", Color: "a2eeef", Description: "New feature or request"},
+			},
+			Workflow: &devdashpb.Workflow{Statuses: []*devdashpb.WorkflowStatus{
+				{Name: "New", Category: devdashpb.StatusCategory_TODO},
+				{Name: "In Progress", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Review", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Done", Category: devdashpb.StatusCategory_DONE},
+			}},
 			Milestones: []*devdashpb.TrackerMilestone{
 				{
 					Id:          "def201901",
@@ -92,6 +122,9 @@ func main() {
 			Updated:   pbTimestamp("2018-12-24T21:51"),
 			Assignees: []*devdashpb.TrackerUser{{Id: "urld", Name: "David Url", Email: "david@urld.io"}},
 			Owner:     &devdashpb.TrackerUser{Id: "urld", Name: "David Url", Email: "david@urld.io"},
+			Priority:  "High",
+			Type:      "task",
+			Estimate:  &devdashpb.Int64Change{Val: 4 * 3600},
 		},
 	})
 	log(&devdashpb.Mutation{
@@ -101,6 +134,9 @@ func main() {
 			IssueKey: "ABC-2",
 			Title:    "service specs",
 			Body:     "REST service specification",
+			Labels:   []*devdashpb.TrackerLabel{{Name: "documentation"}},
+			Priority: "Low",
+			Type:     "task",
 			Status:   "New",
 			Created:  pbTimestamp("2018-12-10T14:13"),
 			Updated:  pbTimestamp("2018-12-24T21:51"),
@@ -109,16 +145,19 @@ func main() {
 	})
 	log(&devdashpb.Mutation{
 		Issue: &devdashpb.IssueMutation{
-			Id:        "i3",
-			Project:   "DEF",
-			IssueKey:  "DEF-1",
-			Title:     "client prototype",
-			Body:      "prototype of a http client for service x",
-			Status:    "In Progress",
-			Created:   pbTimestamp("2018-12-11T11:13"),
-			Updated:   pbTimestamp("2018-12-26T19:21"),
-			Assignees: []*devdashpb.TrackerUser{{Id: "urld", Name: "David Url", Email: "david@urld.io"}},
-			Owner:     &devdashpb.TrackerUser{Id: "urld", Name: "David Url", Email: "david@urld.io"},
+			Id:          "i3",
+			Project:     "DEF",
+			IssueKey:    "DEF-1",
+			Title:       "client prototype",
+			Body:        "prototype of a http client for service x",
+			Status:      "In Progress",
+			Priority:    "Medium",
+			Type:        "story",
+			StoryPoints: &devdashpb.DoubleChange{Val: 5},
+			Created:     pbTimestamp("2018-12-11T11:13"),
+			Updated:     pbTimestamp("2018-12-26T19:21"),
+			Assignees:   []*devdashpb.TrackerUser{{Id: "urld", Name: "David Url", Email: "david@urld.io"}},
+			Owner:       &devdashpb.TrackerUser{Id: "urld", Name: "David Url", Email: "david@urld.io"},
 		},
 	})
 	log(&devdashpb.Mutation{
@@ -129,6 +168,7 @@ func main() {
 			FreezeDate:  pbTimestamp("2018-12-26T12:00"),
 			ReleaseDate: pbTimestamp("2019-01-10T18:00"),
 			Milestones:  []*devdashpb.TrackerMilestone{{Id: "def201901"}},
+			TagPattern:  "v2019.01*",
 		},
 	})
 	log(&devdashpb.Mutation{
@@ -139,6 +179,7 @@ func main() {
 			FreezeDate:  pbTimestamp("2019-02-13T18:00"),
 			ReleaseDate: pbTimestamp("2019-02-20T18:00"),
 			Milestones:  []*devdashpb.TrackerMilestone{{Id: "def201902"}, {Id: "abc201902"}},
+			TagPattern:  "v2019.02*",
 		},
 	})
 	log(&devdashpb.Mutation{
@@ -172,16 +213,28 @@ func main() {
 	})
 	log(&devdashpb.Mutation{
 		Issue: &devdashpb.IssueMutation{
-			Id:         "i4",
-			Project:    "DEF",
-			IssueKey:   "DEF-2",
-			Title:      "Improve client API",
+			Id:       "i4",
+			Project:  "DEF",
+			IssueKey: "DEF-2",
+			Title:    "Improve client API",
+			Labels:   []*devdashpb.TrackerLabel{{Name: "enhancement"}},
+			Type:     "story",
+			CustomFields: []*devdashpb.CustomField{
+				{Key: "component", Value: &devdashpb.CustomField_StringVal{StringVal: "client"}},
+			},
 			Body:       "the client api needs some improvement after the initial prototype is hard to use (see DEF-1)",
 			Status:     "New",
 			Created:    pbTimestamp("2018-12-11T11:13"),
 			Updated:    pbTimestamp("2018-12-26T19:21"),
 			Owner:      &devdashpb.TrackerUser{Id: "urld", Name: "David Url", Email: "david@urld.io"},
 			Milestones: []*devdashpb.TrackerMilestone{{Id: "def201902"}},
+			Links:      []*devdashpb.IssueLink{{Issue: "i3", Type: devdashpb.IssueLinkType_RELATES}},
+		},
+	})
+	log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:    "i3",
+			Links: []*devdashpb.IssueLink{{Issue: "i4", Type: devdashpb.IssueLinkType_BLOCKS}},
 		},
 	})
 	log(&devdashpb.Mutation{
@@ -190,6 +243,7 @@ func main() {
 			Project:    "DEF",
 			IssueKey:   "DEF-3",
 			Title:      "Null pointer if network is down",
+			Labels:     []*devdashpb.TrackerLabel{{Name: "bug"}},
 			Body:       "add null checks and recover from network connectivity issues",
 			Status:     "New",
 			Created:    pbTimestamp("2018-12-18T11:02"),
@@ -198,6 +252,132 @@ func main() {
 			Milestones: []*devdashpb.TrackerMilestone{{Id: "def201902"}},
 		},
 	})
+	log(&devdashpb.Mutation{
+		Sprint: &devdashpb.SprintMutation{
+			Id:           "s1",
+			Project:      "ABC",
+			Name:         "ABC Sprint 1",
+			Goal:         "project setup",
+			StartDate:    pbTimestamp("2018-12-17T09:00"),
+			EndDate:      pbTimestamp("2018-12-28T18:00"),
+			CompleteDate: pbTimestamp("2018-12-28T17:30"),
+			State:        devdashpb.SprintState_CLOSED,
+			Issues: []*devdashpb.SprintIssue{
+				{Issue: "i1", Added: pbTimestamp("2018-12-14T10:00")},
+				{Issue: "i2", Added: pbTimestamp("2018-12-14T10:00")},
+			},
+		},
+	})
+	log(&devdashpb.Mutation{
+		Sprint: &devdashpb.SprintMutation{
+			Id:        "s2",
+			Project:   "ABC",
+			Name:      "ABC Sprint 2",
+			Goal:      "service specification",
+			StartDate: pbTimestamp("2018-12-31T09:00"),
+			EndDate:   pbTimestamp("2019-01-11T18:00"),
+			State:     devdashpb.SprintState_ACTIVE,
+			Issues: []*devdashpb.SprintIssue{
+				{Issue: "i2", Added: pbTimestamp("2018-12-28T17:30")},
+			},
+		},
+	})
+	log(&devdashpb.Mutation{
+		Sprint: &devdashpb.SprintMutation{
+			Id:        "s3",
+			Project:   "DEF",
+			Name:      "DEF Sprint 1",
+			Goal:      "usable client prototype",
+			StartDate: pbTimestamp("2018-12-17T09:00"),
+			EndDate:   pbTimestamp("2018-12-28T18:00"),
+			State:     devdashpb.SprintState_ACTIVE,
+			Issues: []*devdashpb.SprintIssue{
+				{Issue: "i3", Added: pbTimestamp("2018-12-14T10:00")},
+				{Issue: "i5", Added: pbTimestamp("2018-12-18T13:14")},
+			},
+		},
+	})
+	log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: "https://github.com/urld/abc.git",
+			Commit: &devdashpb.GitCommit{
+				Sha1: "9b1c0a3a0f0f6b3b7e2c4a1d8e5f6a7b8c9d0e1f",
+				Raw: "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+					"author David Url <david@urld.io> 1544451180 +0100\n" +
+					"committer David Url <david@urld.io> 1544451180 +0100\n" +
+					"\n" +
+					"ABC-1: initial commit\n",
+				DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+					{File: "README.md", Added: 12},
+					{File: ".travis.yml", Added: 8},
+				}},
+			},
+		},
+	})
+	log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: "https://github.com/urld/abc.git",
+			Commit: &devdashpb.GitCommit{
+				Sha1: "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e",
+				Raw: "tree 5c0a5e9a7ff1b0c2f5d6e7a8b9c0d1e2f3a4b5c6\n" +
+					"parent 9b1c0a3a0f0f6b3b7e2c4a1d8e5f6a7b8c9d0e1f\n" +
+					"author David Url <david.url@example.com> 1545729060 +0100\n" +
+					"committer David Url <david.url@example.com> 1545729060 +0100\n" +
+					"\n" +
+					"ABC-1: configure ci build\n",
+				DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+					{File: ".travis.yml", Added: 4, Deleted: 2},
+				}},
+			},
+			Refs: []*devdashpb.GitRef{
+				{Ref: "HEAD", Sha1: "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e"},
+				{Ref: "refs/heads/master", Sha1: "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e"},
+				{Ref: "refs/tags/v2019.01.0", Sha1: "3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e"},
+			},
+		},
+	})
+	log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: "https://github.com/urld/abc.git",
+			Commit: &devdashpb.GitCommit{
+				Sha1: "7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a",
+				Raw: "tree 6d1b6f0a8e9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e\n" +
+					"parent 3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e\n" +
+					"author David Url <david@urld.io> 1550847600 +0100\n" +
+					"committer David Url <david@urld.io> 1550847600 +0100\n" +
+					"\n" +
+					"update changelog for 2019.02\n",
+				DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+					{File: "CHANGELOG.md", Added: 9},
+				}},
+			},
+			Refs: []*devdashpb.GitRef{
+				{Ref: "HEAD", Sha1: "7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a"},
+				{Ref: "refs/heads/master", Sha1: "7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a"},
+				{Ref: "refs/tags/v2019.02.0", Sha1: "7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a"},
+			},
+		},
+	})
+	log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: "https://github.com/urld/abc.git",
+			Commit: &devdashpb.GitCommit{
+				Sha1: "e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6",
+				Raw: "tree 8f7e6d5c4b3a29180f7e6d5c4b3a29180f7e6d5c\n" +
+					"parent 3f2e1d0c9b8a7f6e5d4c3b2a1f0e9d8c7b6a5f4e\n" +
+					"author David Url <david@urld.io> 1546596000 +0100\n" +
+					"committer David Url <david@urld.io> 1546596000 +0100\n" +
+					"\n" +
+					"ABC-2: draft service specification\n",
+				DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+					{File: "docs/service.md", Added: 42},
+				}},
+			},
+			Refs: []*devdashpb.GitRef{
+				{Ref: "refs/heads/feature/ABC-2-service-specs", Sha1: "e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6"},
+			},
+		},
+	})
 }
 
 func pbTimestamp(s string) *timestamp.Timestamp {
@@ -207,6 +387,16 @@ func pbTimestamp(s string) *timestamp.Timestamp {
 	return ts
 }
 
+// clearDir removes everything but the lock file from dir.
+func clearDir(dir string) {
+	fis, _ := ioutil.ReadDir(dir)
+	for _, fi := range fis {
+		if fi.Name() != devdashboard.LockFile {
+			os.RemoveAll(filepath.Join(dir, fi.Name()))
+		}
+	}
+}
+
 func targetDir() string {
 	dir := *dataPath
 	if dir == "" {
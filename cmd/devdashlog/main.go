@@ -0,0 +1,390 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The devdashlog command inspects the mutation log directory written by
+// devdashsync and devdashboard.
+//
+// Usage:
+//
+//	devdashlog [-data dir] ls
+//	devdashlog [-data dir] [-json] dump [file...]
+//	devdashlog [-data dir] [-json] grep id...
+//	devdashlog [-data dir] verify
+//	devdashlog [-data dir] stats
+//
+// The ls command lists the log files with their sizes and record counts.
+// The dump command prints all records of the log, or of the given log
+// files and snapshots, with their offsets. The grep command prints the
+// records of the projects, releases, issues, sprints, git repositories
+// and commits with the given IDs, such as "ABC-1" or a commit hash.
+// Records are printed as text, or as one JSON object per line with -json.
+//
+// The verify command checks the record headers, offsets and checksums of
+// all log files and snapshots, decodes all mutations and checks the corpus
+// built from them. It exits with status 1 if the log is broken. The stats
+// command counts the mutations by type.
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/urld/devdashboard"
+	"github.com/urld/devdashboard/devdashdata"
+	"github.com/urld/devdashboard/devdashpb"
+	"github.com/urld/devdashboard/reclog"
+)
+
+var (
+	dataPath = flag.String("data", "", "data path")
+	jsonOut  = flag.Bool("json", false, "print records as JSON, one object per line")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: devdashlog [flags] ls|dump [file...]|grep id...|verify|stats\n\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	dir := *dataPath
+	if dir == "" {
+		dir = devdashdata.DefaultDir()
+	}
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+
+	var err error
+	switch cmd {
+	case "ls":
+		err = ls(dir)
+	case "dump":
+		if len(args) == 0 {
+			args, err = logFiles(dir)
+		}
+		for n, path := range args {
+			// file names are relative to the data path
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				args[n] = filepath.Join(dir, path)
+			}
+		}
+		if err == nil {
+			err = dump(args, func(*devdashpb.Mutation) bool { return true })
+		}
+	case "grep":
+		if len(args) == 0 {
+			usage()
+			os.Exit(2)
+		}
+		var files []string
+		if files, err = logFiles(dir); err == nil {
+			err = dump(files, func(m *devdashpb.Mutation) bool { return matches(m, args) })
+		}
+	case "verify":
+		err = verify(dir)
+	case "stats":
+		err = stats(dir)
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// logFiles returns the paths of all log files in dir, in the order they
+// were written.
+func logFiles(dir string) ([]string, error) {
+	var files []string
+	err := devdashboard.NewDiskMutationLogger(dir).ForeachFile(func(fullPath string, fi os.FileInfo) error {
+		files = append(files, fullPath)
+		return nil
+	})
+	return files, err
+}
+
+// snapshotFiles returns the paths of all snapshots in dir, the newest
+// last.
+func snapshotFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "devdashboard-*.snapshot"))
+	sort.Strings(files)
+	return files, err
+}
+
+// foreachRecord calls fn for each record of the named log file or
+// snapshot, which may be compressed.
+func foreachRecord(path string, fn reclog.RecordCallback) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		r = zr
+	}
+	return reclog.ForeachRecord(r, 0, fn)
+}
+
+// foreachMutation calls fn for each mutation of the named file.
+func foreachMutation(path string, fn func(off int64, m *devdashpb.Mutation) error) error {
+	return foreachRecord(path, func(off int64, hdr, rec []byte) error {
+		m := new(devdashpb.Mutation)
+		if err := proto.Unmarshal(rec, m); err != nil {
+			return fmt.Errorf("record at offset %d: %v", off, err)
+		}
+		return fn(off, m)
+	})
+}
+
+func ls(dir string) error {
+	files, err := logFiles(dir)
+	if err != nil {
+		return err
+	}
+	snapshots, err := snapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "SIZE\tRECORDS\t\tFILE\n")
+	var size int64
+	var records int
+	for _, path := range append(files, snapshots...) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		n := 0
+		err = foreachRecord(path, func(off int64, hdr, rec []byte) error {
+			n++
+			return nil
+		})
+		note := ""
+		if err != nil {
+			note = fmt.Sprintf(" (%v)", err)
+		}
+		fmt.Fprintf(tw, "%d\t%d\t\t%s%s\n", fi.Size(), n, filepath.Base(path), note)
+		if !strings.HasSuffix(path, ".snapshot") {
+			size += fi.Size()
+			records += n
+		}
+	}
+	fmt.Fprintf(tw, "%d\t%d\t\ttotal of %d log files\n", size, records, len(files))
+	return tw.Flush()
+}
+
+// dump prints the mutations of files for which match returns true.
+func dump(files []string, match func(*devdashpb.Mutation) bool) error {
+	w := os.Stdout
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	for _, path := range files {
+		name := filepath.Base(path)
+		err := foreachMutation(path, func(off int64, m *devdashpb.Mutation) error {
+			if !match(m) {
+				return nil
+			}
+			if !*jsonOut {
+				_, err := fmt.Fprintf(w, "%s:%d: %s %s\n", name, off, kind(m), proto.CompactTextString(m))
+				return err
+			}
+			data, err := marshaler.MarshalToString(m)
+			if err != nil {
+				return err
+			}
+			rec, err := json.Marshal(struct {
+				File     string          `json:"file"`
+				Offset   int64           `json:"offset"`
+				Mutation json.RawMessage `json:"mutation"`
+			}{name, off, json.RawMessage(data)})
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(w, "%s\n", rec)
+			return err
+		})
+		if te, ok := err.(*reclog.TruncatedError); ok {
+			log.Printf("%s: torn record at offset %d", path, te.Offset)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// kind returns the type of the mutation, such as "issue" or "git-refs".
+func kind(m *devdashpb.Mutation) string {
+	var kinds []string
+	if m.Project != nil {
+		kinds = append(kinds, "project")
+	}
+	if m.Release != nil {
+		kinds = append(kinds, "release")
+	}
+	if m.Issue != nil {
+		kinds = append(kinds, "issue")
+	}
+	if m.Sprint != nil {
+		kinds = append(kinds, "sprint")
+	}
+	if gm := m.Git; gm != nil {
+		if gm.Commit != nil {
+			kinds = append(kinds, "git-commit")
+		}
+		if len(gm.Refs) > 0 || len(gm.DeletedRefs) > 0 {
+			kinds = append(kinds, "git-refs")
+		}
+		if gm.Commit == nil && len(gm.Refs) == 0 && len(gm.DeletedRefs) == 0 {
+			kinds = append(kinds, "git")
+		}
+	}
+	if len(kinds) == 0 {
+		return "empty"
+	}
+	return strings.Join(kinds, "+")
+}
+
+// matches reports whether m mutates an entity with one of the ids.
+func matches(m *devdashpb.Mutation, ids []string) bool {
+	var keys []string
+	if pm := m.Project; pm != nil {
+		keys = append(keys, pm.Id)
+	}
+	if rm := m.Release; rm != nil {
+		keys = append(keys, rm.Id)
+	}
+	if im := m.Issue; im != nil {
+		keys = append(keys, im.Id, im.IssueKey)
+	}
+	if sm := m.Sprint; sm != nil {
+		keys = append(keys, sm.Id)
+	}
+	if gm := m.Git; gm != nil {
+		keys = append(keys, gm.Repo)
+		if gm.Commit != nil {
+			keys = append(keys, gm.Commit.Sha1)
+		}
+	}
+	for _, id := range ids {
+		for _, key := range keys {
+			if key != "" && key == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func verify(dir string) error {
+	files, err := logFiles(dir)
+	if err != nil {
+		return err
+	}
+	snapshots, err := snapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+	broken := false
+	for n, path := range append(files, snapshots...) {
+		records := 0
+		err := foreachMutation(path, func(off int64, m *devdashpb.Mutation) error {
+			records++
+			return nil
+		})
+		if te, ok := err.(*reclog.TruncatedError); ok && n == len(files)-1 {
+			// The record may still be written, or is removed by the
+			// next logger appending to the file.
+			fmt.Printf("%s: %d records, torn record at offset %d\n", path, records, te.Offset)
+			continue
+		}
+		if err != nil {
+			fmt.Printf("%s: %d records, %v\n", path, records, err)
+			broken = true
+			continue
+		}
+		fmt.Printf("%s: %d records, ok\n", path, records)
+	}
+
+	// The corpus is built from the newest snapshot, like any reader does.
+	log.SetOutput(ioutil.Discard)
+	c := new(devdashboard.Corpus)
+	err = c.Initialize(context.Background(), devdashboard.NewDiskMutationLogger(dir))
+	log.SetOutput(os.Stderr)
+	if err == nil {
+		err = c.Check()
+	}
+	if err != nil {
+		fmt.Printf("corpus: %v\n", err)
+		broken = true
+	} else {
+		fmt.Printf("corpus: %d projects, %d issues, %d sprints, %d git repos, ok\n",
+			len(c.Projects), len(c.Issues), len(c.Sprints), len(c.GitRepos))
+	}
+	if broken {
+		return fmt.Errorf("%s is broken", dir)
+	}
+	return nil
+}
+
+func stats(dir string) error {
+	files, err := logFiles(dir)
+	if err != nil {
+		return err
+	}
+	counts := make(map[string]int)
+	var total int
+	var size int64
+	for _, path := range files {
+		err := foreachRecord(path, func(off int64, hdr, rec []byte) error {
+			m := new(devdashpb.Mutation)
+			if err := proto.Unmarshal(rec, m); err != nil {
+				return fmt.Errorf("record at offset %d: %v", off, err)
+			}
+			counts[kind(m)]++
+			total++
+			size += int64(len(hdr) + len(rec))
+			return nil
+		})
+		if _, ok := err.(*reclog.TruncatedError); err != nil && !ok {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	kinds := make([]string, 0, len(counts))
+	for k := range counts {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "MUTATIONS\t\tTYPE\n")
+	for _, k := range kinds {
+		fmt.Fprintf(tw, "%d\t\t%s\n", counts[k], k)
+	}
+	fmt.Fprintf(tw, "%d\t\ttotal in %d log files, %d bytes uncompressed\n", total, len(files), size)
+	return tw.Flush()
+}
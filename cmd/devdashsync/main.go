@@ -0,0 +1,178 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The devdashsync command syncs issue trackers and git repositories into
+// the mutation log directory read by devdashboard and devdashdata.Get.
+//
+// Usage:
+//
+//	devdashsync -config devdashboard.json [-data dir] [-once]
+//	devdashsync [-data dir] [-once] [-interval 5m] repo-url...
+//	devdashsync -snapshot [-compress] [-data dir]
+//
+// By default, devdashsync runs as daemon and syncs periodically as
+// configured. With -once, it syncs everything once and exits, which is
+// useful to run it from cron.
+//
+// With -http, the daemon serves the mutation logs, so dashboards and other
+// tools can replicate them without access to the synced sources.
+//
+// With -snapshot, devdashsync writes a snapshot of the mutation log and
+// exits. Loading the corpus starts from the newest snapshot, instead of
+// replaying the whole log. With -compress, it gzips the log files of past
+// days and exits. Run both from cron, such as once a day.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/urld/devdashboard"
+	"github.com/urld/devdashboard/devdashconfig"
+	"github.com/urld/devdashboard/devdashdata"
+)
+
+var (
+	configPath = flag.String("config", "", "configuration file, see package devdashconfig")
+	dataPath   = flag.String("data", "", "data path, overrides server.data of the configuration")
+	once       = flag.Bool("once", false, "sync once and exit instead of running as daemon")
+	snapshot   = flag.Bool("snapshot", false, "write a snapshot of the mutation log and exit")
+	compress   = flag.Bool("compress", false, "compress the log files of past days and exit")
+	httpAddr   = flag.String("http", "", "serve the mutation logs for devdashboard -upstream on this address (e.g., ':8081')")
+	interval   = flag.Duration("interval", 0, "sync interval of repositories given as arguments, overrides sync.interval")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: devdashsync [flags] [repo-url...]\n\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	cfg := devdashconfig.Default()
+	if *configPath != "" {
+		var err error
+		cfg, err = devdashconfig.Load(*configPath)
+		if err != nil {
+			log.Fatalf("unable to load configuration:\n%v", err)
+		}
+	}
+	for _, url := range flag.Args() {
+		r := devdashconfig.Repo{URL: url}
+		r.Interval.Duration = cfg.Sync.Interval.Duration
+		if *interval > 0 {
+			r.Interval.Duration = *interval
+		}
+		cfg.Repos = append(cfg.Repos, r)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("invalid configuration:\n%v", err)
+	}
+
+	dir := *dataPath
+	if dir == "" {
+		dir = cfg.Server.Data
+	}
+	if dir == "" {
+		dir = devdashdata.DefaultDir()
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Fatal(err)
+	}
+
+	if *snapshot || *compress {
+		maintain(cfg.NewLogger(dir))
+		return
+	}
+
+	s := cfg.NewScheduler(dir)
+	if s == nil {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt)
+		<-sig
+		log.Printf("interrupted, stopping...")
+		cancel()
+	}()
+
+	// The corpus tells the syncers what is logged already, so they only
+	// log new changes after a restart.
+	logger := cfg.NewLogger(dir)
+	if err := logger.Acquire(); err != nil {
+		log.Fatalf("unable to sync: %v", err)
+	}
+	c := new(devdashboard.Corpus)
+	if err := c.Initialize(ctx, logger); err != nil {
+		log.Fatalf("unable to initialize corpus: %v", err)
+	}
+	s.Corpus = c
+	s.Logger = logger
+	s.Synced = c.Update
+
+	if *once {
+		err := s.SyncAll(ctx)
+		printStatus(s)
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+	if *httpAddr != "" {
+		mux := http.NewServeMux()
+		h := devdashboard.LogHandler(logger)
+		mux.Handle("/logs", h)
+		mux.Handle("/logs/", h)
+		go func() {
+			log.Fatal(http.ListenAndServe(*httpAddr, mux))
+		}()
+	}
+	log.Printf("syncing into %s...", dir)
+	s.Run(ctx)
+	printStatus(s)
+}
+
+// maintain writes a snapshot and compresses the log as requested by the
+// flags.
+func maintain(logger *devdashboard.DiskMutationLogger) {
+	if *snapshot {
+		filename, err := logger.WriteSnapshot(context.Background())
+		if err != nil {
+			log.Fatalf("unable to write snapshot: %v", err)
+		}
+		log.Printf("wrote %s", filename)
+	}
+	if *compress {
+		files, err := logger.Compress()
+		for _, filename := range files {
+			log.Printf("wrote %s", filename)
+		}
+		if err != nil {
+			log.Fatalf("unable to compress log: %v", err)
+		}
+	}
+}
+
+func printStatus(s *devdashboard.Scheduler) {
+	for _, st := range s.Status() {
+		state := "ok"
+		if st.Failures > 0 {
+			state = "failed: " + st.LastError
+		}
+		log.Printf("%s: %s (%v)", st.Name, state, st.Duration.Round(time.Millisecond))
+	}
+}
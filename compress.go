@@ -0,0 +1,88 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Compress gzips all sealed log files, which are all but the newest log
+// file and the one Log currently appends to, and returns the paths of the
+// compressed files. The manifest is updated accordingly. Compressed log
+// files are read like uncompressed ones, also by loggers of other processes.
+func (d *DiskMutationLogger) Compress() ([]string, error) {
+	var files []string
+	err := d.ForeachFile(func(fullPath string, fi os.FileInfo) error {
+		files = append(files, fullPath)
+		return nil
+	})
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+	d.mu.Lock()
+	current := d.current
+	d.mu.Unlock()
+	var compressed []string
+	for _, path := range files[:len(files)-1] {
+		if segmentPath(path) != path || path == current {
+			continue
+		}
+		if err := compressFile(path); err != nil {
+			return compressed, err
+		}
+		compressed = append(compressed, path+compressedSuffix)
+	}
+	if len(compressed) > 0 {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return compressed, d.writeManifest()
+	}
+	return compressed, nil
+}
+
+// compressFile replaces the named file by its gzipped version.
+func compressFile(path string) (err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := ioutil.TempFile(filepath.Dir(path), "tmp-compress-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(out.Name())
+		}
+	}()
+	zw, err := gzip.NewWriterLevel(out, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	zw.Name = filepath.Base(path)
+	if _, err := io.Copy(zw, in); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// readers skip the compressed file until the uncompressed one is gone
+	if err := os.Rename(out.Name(), path+compressedSuffix); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/urld/devdashboard/devdashpb"
+	"github.com/urld/devdashboard/reclog"
+)
+
+// countMutations returns the number of mutations sent by the source
+// before its End event.
+func countMutations(t *testing.T, src MutationSource) int {
+	n := 0
+	for e := range src.GetMutations(context.Background()) {
+		if e.Err != nil {
+			t.Fatal(e.Err)
+		}
+		if e.End {
+			return n
+		}
+		n++
+	}
+	return n
+}
+
+func TestCompress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	old := filepath.Join(dir, "devdashboard-2019-01-01.mutlog")
+	for _, id := range []string{"ABC", "DEF"} {
+		data, err := proto.Marshal(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: id}})
+		checkErr(t, err)
+		checkErr(t, reclog.AppendRecordToFile(old, data))
+	}
+	d := NewDiskMutationLogger(dir)
+	checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "GHI"}}))
+	if n := countMutations(t, d); n != 3 {
+		t.Fatalf("expected 3 mutations, got %d", n)
+	}
+
+	files, err := d.Compress()
+	checkErr(t, err)
+	if len(files) != 1 || files[0] != old+".gz" {
+		t.Fatalf("expected %s.gz, got %v", old, files)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("%s should be removed", old)
+	}
+	if n := countMutations(t, d); n != 0 {
+		t.Errorf("compressed mutations must not be sent again, got %d", n)
+	}
+
+	c := new(Corpus)
+	checkErr(t, c.Initialize(context.Background(), NewDiskMutationLogger(dir)))
+	if len(c.Projects) != 3 {
+		t.Errorf("expected 3 projects, got %v", c.Projects)
+	}
+	if files, err := d.Compress(); err != nil || len(files) != 0 {
+		t.Errorf("the current log file must not be compressed, got %v, %v", files, err)
+	}
+}
@@ -6,6 +6,7 @@ package devdashboard
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"sync"
 
@@ -26,10 +27,18 @@ type Corpus struct {
 	TrackerUsers map[string]*IssueTrackerUser
 	Milestones   map[string]*Milestone
 	Releases     map[string]*Release
+	Sprints      map[string]*Sprint
 	Issues       map[string]*Issue
 
 	// source data:
 	GitRepos map[string]*GitRepo
+
+	releaseTagPattern string // see SetReleaseTagPattern
+
+	// indexes:
+	issuesByKey       map[string]*Issue
+	commitsByIssueKey map[string][]*GitCommit
+	issueLinksTo      map[string][]IssueLink // reverse index of Issue.Links
 }
 
 // RLock grabs the corpus's read lock. Grabbing the read lock prevents
@@ -54,10 +63,15 @@ func (c *Corpus) Initialize(ctx context.Context, src MutationSource) error {
 	c.TrackerUsers = make(map[string]*IssueTrackerUser)
 	c.Milestones = make(map[string]*Milestone)
 	c.Releases = make(map[string]*Release)
+	c.Sprints = make(map[string]*Sprint)
 	c.Issues = make(map[string]*Issue)
 
 	c.GitRepos = make(map[string]*GitRepo)
 
+	c.issuesByKey = make(map[string]*Issue)
+	c.commitsByIssueKey = make(map[string][]*GitCommit)
+	c.issueLinksTo = make(map[string][]IssueLink)
+
 	log.Printf("Loading data from log %T ...", src)
 	return c.update(ctx, nil)
 }
@@ -88,8 +102,14 @@ func (c *Corpus) update(ctx context.Context, lk sync.Locker) error {
 	src := c.mutationSource
 	mutations := src.GetMutations(ctx)
 	done := ctx.Done()
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	// The lock is grabbed by the first event, so readers are not blocked
+	// while the source waits for new mutations, like NetworkMutationSource.
+	locked := false
+	defer func() {
+		if locked {
+			c.mu.Unlock()
+		}
+	}()
 	if lk == nil {
 		lk = noopLocker{}
 	}
@@ -100,6 +120,10 @@ func (c *Corpus) update(ctx context.Context, lk sync.Locker) error {
 			log.Printf("Context expired while loading data from log %T: %v", src, err)
 			return err
 		case e := <-mutations:
+			if !locked {
+				c.mu.Lock()
+				locked = true
+			}
 			if e.Err != nil {
 				log.Printf("Corpus GetMutations: %v", e.Err)
 				return e.Err
@@ -148,11 +172,61 @@ func (c *Corpus) processMutationLocked(m *devdashpb.Mutation) {
 	if gm := m.Git; gm != nil {
 		c.processGitMutation(gm)
 	}
+	if sm := m.Sprint; sm != nil {
+		c.processSprintMutation(sm)
+	}
 }
 
 // Check verifies the internal structure of the Corpus data structures.
 // It is intended for tests and debugging.
 func (c *Corpus) Check() error {
-	// TODO
+	for id, p := range c.Projects {
+		if p.ID != id {
+			return fmt.Errorf("project %q is indexed as %q", p.ID, id)
+		}
+		for id, i := range p.Issues {
+			if c.Issues[id] != i || i.p != p {
+				return fmt.Errorf("issue %q of project %q is not linked back", id, p.ID)
+			}
+		}
+		for id, m := range p.Milestones {
+			if m.ID != id || m.p != p {
+				return fmt.Errorf("milestone %q of project %q is not linked back", id, p.ID)
+			}
+		}
+	}
+	for id, i := range c.Issues {
+		if i.ID != id {
+			return fmt.Errorf("issue %q is indexed as %q", i.ID, id)
+		}
+		if i.p != nil && (c.Projects[i.p.ID] != i.p || i.p.Issues[id] != i) {
+			return fmt.Errorf("issue %q is missing in its project %q", id, i.p.ID)
+		}
+		for mid, m := range i.Milestones {
+			if m.ID != mid || m.Issues[id] != i {
+				return fmt.Errorf("issue %q is missing in its milestone %q", id, mid)
+			}
+		}
+	}
+	for key, i := range c.issuesByKey {
+		if i.IssueKey != key || c.Issues[i.ID] != i {
+			return fmt.Errorf("issue %q is indexed by key %q, but has key %q", i.ID, key, i.IssueKey)
+		}
+	}
+	for id, s := range c.Sprints {
+		if s.ID != id {
+			return fmt.Errorf("sprint %q is indexed as %q", s.ID, id)
+		}
+	}
+	for url, r := range c.GitRepos {
+		if r.URL != url {
+			return fmt.Errorf("git repo %q is indexed as %q", r.URL, url)
+		}
+		for sha1, gc := range r.commits {
+			if gc.Sha1 != sha1 || gc.r != r {
+				return fmt.Errorf("commit %s of %s is indexed as %s", gc.Sha1, url, sha1)
+			}
+		}
+	}
 	return nil
 }
@@ -0,0 +1,474 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package devdashconfig loads the configuration of the devdashboard server
+// and its syncers.
+//
+// The configuration is a JSON file listing the issue tracker sources and git
+// repositories to sync, how often to sync them, and options of the web UI:
+//
+//	{
+//	  "server": {"http": "127.0.0.1:8080", "data": "/var/lib/devdashboard"},
+//	  "sync": {"interval": "5m", "jitter": "30s", "maxBackoff": "1h"},
+//	  "sources": [{
+//	    "name": "jira",
+//	    "type": "jira",
+//	    "url": "https://jira.example.com",
+//	    "credentialsEnv": "JIRA_TOKEN",
+//	    "projects": ["ABC", "DEF"],
+//	    "workflow": {"Open": "todo", "In Review": "in_progress", "Resolved": "done"}
+//	  }],
+//	  "repos": [{"url": "https://github.com/urld/abc.git", "interval": "1m"}],
+//	  "log": {"period": "24h", "maxSizeMB": 64},
+//	  "ui": {"metricsDays": 30, "staleBranchDays": 14, "releaseTagPattern": "v{name}"}
+//	}
+package devdashconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config is the configuration of the devdashboard server and its syncers.
+type Config struct {
+	Server  Server   `json:"server"`
+	Sync    Sync     `json:"sync"`
+	Sources []Source `json:"sources"`
+	Repos   []Repo   `json:"repos"`
+	Log     Log      `json:"log"`
+	UI      UI       `json:"ui"`
+}
+
+// Server configures the devdashboard server.
+type Server struct {
+	HTTP    string `json:"http"`    // HTTP service address
+	Base    string `json:"base"`    // base path for html templates and static resources
+	Data    string `json:"data"`    // directory of the mutation logs
+	Aliases string `json:"aliases"` // file mapping issue tracker users to additional git emails
+
+	// Upstream is the URL of a log server, such as devdashsync -http, to
+	// replicate the mutation logs from into the data directory.
+	Upstream string `json:"upstream"`
+}
+
+// Sync configures when sources and repositories are synced.
+type Sync struct {
+	Interval   Duration `json:"interval"`   // time between two syncs, 5m by default
+	Jitter     Duration `json:"jitter"`     // maximum random delay added to the interval
+	MaxBackoff Duration `json:"maxBackoff"` // maximum delay after failed syncs, 1h by default
+}
+
+// Source is an issue tracker to sync.
+type Source struct {
+	Name           string            `json:"name"` // unique name, required
+	Type           string            `json:"type"` // one of SourceTypes, required
+	URL            string            `json:"url"`  // required
+	CredentialsEnv string            `json:"credentialsEnv"`
+	Projects       []string          `json:"projects"` // project keys to sync, all if empty
+	Workflow       map[string]string `json:"workflow"` // tracker status -> status category
+	Interval       Duration          `json:"interval"` // overrides the sync interval
+}
+
+// Credentials returns the value of the environment variable holding the
+// credentials of the source.
+func (s *Source) Credentials() string {
+	if s.CredentialsEnv == "" {
+		return ""
+	}
+	return os.Getenv(s.CredentialsEnv)
+}
+
+// Repo is a git repository to sync.
+type Repo struct {
+	URL      string   `json:"url"`      // required
+	Dir      string   `json:"dir"`      // local clone, below the data directory by default
+	Interval Duration `json:"interval"` // overrides the sync interval
+}
+
+// Log configures when a new mutation log file is started.
+type Log struct {
+	Period     Duration `json:"period"`     // time period of a file, aligned to UTC, 24h by default
+	MaxSizeMB  int      `json:"maxSizeMB"`  // start a new file once the current one has this size
+	MaxRecords int      `json:"maxRecords"` // start a new file once the current one has this many records
+}
+
+// UI configures the web UI.
+type UI struct {
+	MetricsDays     int `json:"metricsDays"`     // default metrics window, 90 by default
+	StaleBranchDays int `json:"staleBranchDays"` // days after which branches are stale, 30 by default
+	Hotspots        int `json:"hotspots"`        // number of listed churn hotspots, 20 by default
+
+	// ReleaseTagPattern is the glob pattern matching the git tags of
+	// releases without tag pattern, such as "release-{name}", where
+	// "{name}" is replaced by the release name. Such releases have no tags
+	// and are never late if empty.
+	ReleaseTagPattern string `json:"releaseTagPattern"`
+}
+
+// SourceTypes lists the supported issue tracker types.
+var SourceTypes = []string{"github", "gitlab", "jira"}
+
+// StatusCategories lists the valid targets of workflow mappings.
+var StatusCategories = []string{"todo", "in_progress", "done"}
+
+const (
+	defaultInterval   = 5 * time.Minute
+	defaultMaxBackoff = time.Hour
+)
+
+// Duration is a time.Duration, which is a string such as "90s" or "1h30m"
+// in JSON.
+type Duration struct {
+	time.Duration
+	err error // parse error, reported by Validate
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Invalid durations
+// are reported by Validate, so the error can name the offending key.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		d.err = fmt.Errorf("expected duration such as \"5m\", got %s", b)
+		return nil
+	}
+	d.Duration, d.err = time.ParseDuration(s)
+	if d.err != nil {
+		d.err = fmt.Errorf("invalid duration %q", s)
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Error is a configuration error of a single key.
+type Error struct {
+	Key string // path of the offending key, such as "sources[1].url"
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Key == "" {
+		return e.Msg
+	}
+	return e.Key + ": " + e.Msg
+}
+
+// Errors lists all errors of a configuration.
+type Errors []*Error
+
+func (errs Errors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Load reads and validates the named configuration file.
+func Load(filename string) (*Config, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return cfg, nil
+}
+
+// Parse parses and validates a configuration and fills in the defaults of
+// all unset options. Unknown keys are rejected.
+func Parse(data []byte) (*Config, error) {
+	cfg := new(Config)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, decodeError(data, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.setDefaults()
+	return cfg, nil
+}
+
+// decodeError adds the position of syntax and type errors to err.
+func decodeError(data []byte, err error) error {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		return &Error{Msg: fmt.Sprintf("%s: %v", position(data, e.Offset), e)}
+	case *json.UnmarshalTypeError:
+		return &Error{
+			Key: e.Field,
+			Msg: fmt.Sprintf("%s: expected %s, got %s", position(data, e.Offset), e.Type, e.Value),
+		}
+	}
+	if strings.HasPrefix(err.Error(), "json: unknown field ") {
+		if e := unknownKeyError(data); e != nil {
+			return e
+		}
+		return &Error{Msg: "unknown key " + strings.TrimPrefix(err.Error(), "json: unknown field ")}
+	}
+	return err
+}
+
+// unknownKeyError returns the path and position of the first key in data,
+// which is not a field of Config, or nil if there is none.
+func unknownKeyError(data []byte) *Error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var unknown *Error
+	var walk func(t reflect.Type, key string) error
+	walk = func(t reflect.Type, key string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+		n := 0
+		for dec.More() {
+			elem, elemKey := anyType, ""
+			if delim == '[' {
+				if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+					elem, elemKey = t.Elem(), fmt.Sprintf("%s[%d]", key, n)
+				}
+				n++
+			} else {
+				tok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				name, _ := tok.(string)
+				switch {
+				case t.Kind() == reflect.Map:
+					elem, elemKey = t.Elem(), fmt.Sprintf("%s[%q]", key, name)
+				case t.Kind() == reflect.Struct && !reflect.PtrTo(t).Implements(unmarshalerType):
+					elemKey = strings.TrimPrefix(key+"."+name, ".")
+					f, ok := jsonField(t, name)
+					if !ok {
+						unknown = &Error{Key: elemKey, Msg: position(data, dec.InputOffset()) + ": unknown key"}
+						return errors.New("unknown key")
+					}
+					elem = f.Type
+				}
+			}
+			if err := walk(elem, elemKey); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	return unknown
+}
+
+var (
+	anyType         = reflect.TypeOf((*interface{})(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// jsonField returns the field of the struct type t, which is decoded from
+// the JSON key name.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// position returns the line and column of the last byte read by the
+// decoder, which stopped after offset bytes.
+func position(data []byte, offset int64) string {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset > 0 {
+		offset--
+	}
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	col := offset - int64(bytes.LastIndexByte(data[:offset], '\n'))
+	return fmt.Sprintf("line %d, column %d", line, col)
+}
+
+// Validate checks the configuration and returns all errors as Errors.
+func (cfg *Config) Validate() error {
+	var errs Errors
+	errorf := func(key, format string, args ...interface{}) {
+		errs = append(errs, &Error{Key: key, Msg: fmt.Sprintf(format, args...)})
+	}
+	checkDuration := func(key string, d Duration) {
+		if d.err != nil {
+			errorf(key, "%v", d.err)
+		} else if d.Duration < 0 {
+			errorf(key, "must not be negative")
+		}
+	}
+
+	if cfg.Server.Upstream != "" {
+		if err := checkURL(cfg.Server.Upstream); err != nil {
+			errorf("server.upstream", "%v", err)
+		}
+	}
+	checkDuration("sync.interval", cfg.Sync.Interval)
+	checkDuration("sync.jitter", cfg.Sync.Jitter)
+	checkDuration("sync.maxBackoff", cfg.Sync.MaxBackoff)
+
+	names := make(map[string]bool)
+	for n, s := range cfg.Sources {
+		key := fmt.Sprintf("sources[%d]", n)
+		switch {
+		case s.Name == "":
+			errorf(key+".name", "missing")
+		case names[s.Name]:
+			errorf(key+".name", "duplicate source %q", s.Name)
+		}
+		names[s.Name] = true
+		if s.Type == "" {
+			errorf(key+".type", "missing")
+		} else if !contains(SourceTypes, s.Type) {
+			errorf(key+".type", "unknown type %q, expected one of %s", s.Type, strings.Join(SourceTypes, ", "))
+		}
+		if err := checkURL(s.URL); err != nil {
+			errorf(key+".url", "%v", err)
+		}
+		statuses := make([]string, 0, len(s.Workflow))
+		for status := range s.Workflow {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			if category := s.Workflow[status]; !contains(StatusCategories, category) {
+				errorf(fmt.Sprintf("%s.workflow[%q]", key, status), "unknown status category %q, expected one of %s",
+					category, strings.Join(StatusCategories, ", "))
+			}
+		}
+		checkDuration(key+".interval", s.Interval)
+	}
+
+	urls := make(map[string]bool)
+	for n, r := range cfg.Repos {
+		key := fmt.Sprintf("repos[%d]", n)
+		if r.URL == "" {
+			errorf(key+".url", "missing")
+		} else if urls[r.URL] {
+			errorf(key+".url", "duplicate repository %q", r.URL)
+		}
+		urls[r.URL] = true
+		checkDuration(key+".interval", r.Interval)
+	}
+
+	checkDuration("log.period", cfg.Log.Period)
+	if p := cfg.Log.Period.Duration; p > 0 && p < time.Minute {
+		errorf("log.period", "must be at least 1m")
+	}
+
+	for key, v := range map[string]int{
+		"log.maxSizeMB":      cfg.Log.MaxSizeMB,
+		"log.maxRecords":     cfg.Log.MaxRecords,
+		"ui.metricsDays":     cfg.UI.MetricsDays,
+		"ui.staleBranchDays": cfg.UI.StaleBranchDays,
+		"ui.hotspots":        cfg.UI.Hotspots,
+	} {
+		if v < 0 {
+			errorf(key, "must not be negative")
+		}
+	}
+	if p := cfg.UI.ReleaseTagPattern; p != "" {
+		if _, err := path.Match(strings.Replace(p, "{name}", "x", -1), ""); err != nil {
+			errorf("ui.releaseTagPattern", "invalid pattern %q", p)
+		}
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Key < errs[j].Key })
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func checkURL(s string) error {
+	if s == "" {
+		return errors.New("missing")
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL %q", s)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg *Config) setDefaults() {
+	if cfg.Sync.Interval.Duration == 0 {
+		cfg.Sync.Interval.Duration = defaultInterval
+	}
+	if cfg.Sync.MaxBackoff.Duration == 0 {
+		cfg.Sync.MaxBackoff.Duration = defaultMaxBackoff
+	}
+	for n := range cfg.Sources {
+		if cfg.Sources[n].Interval.Duration == 0 {
+			cfg.Sources[n].Interval = cfg.Sync.Interval
+		}
+	}
+	for n := range cfg.Repos {
+		if cfg.Repos[n].Interval.Duration == 0 {
+			cfg.Repos[n].Interval = cfg.Sync.Interval
+		}
+	}
+	if cfg.UI.MetricsDays == 0 {
+		cfg.UI.MetricsDays = 90
+	}
+	if cfg.UI.StaleBranchDays == 0 {
+		cfg.UI.StaleBranchDays = 30
+	}
+	if cfg.UI.Hotspots == 0 {
+		cfg.UI.Hotspots = 20
+	}
+}
+
+// Default returns the configuration with all defaults, which is used if
+// no configuration file is given.
+func Default() *Config {
+	cfg := new(Config)
+	cfg.setDefaults()
+	return cfg
+}
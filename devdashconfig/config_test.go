@@ -0,0 +1,80 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashconfig
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte(`{
+  "server": {"http": ":8080"},
+  "sync": {"interval": "10m"},
+  "sources": [{
+    "name": "jira",
+    "type": "jira",
+    "url": "https://jira.example.com",
+    "credentialsEnv": "DEVDASH_TEST_TOKEN",
+    "projects": ["ABC"],
+    "workflow": {"Open": "todo", "Resolved": "done"}
+  }],
+  "repos": [{"url": "https://example.com/abc.git", "interval": "1m"}],
+  "ui": {"staleBranchDays": 14}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.HTTP != ":8080" || cfg.Sources[0].Projects[0] != "ABC" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	if cfg.Sources[0].Interval.Duration != 10*time.Minute || cfg.Repos[0].Interval.Duration != time.Minute {
+		t.Errorf("unexpected intervals %v, %v", cfg.Sources[0].Interval, cfg.Repos[0].Interval)
+	}
+	if cfg.Sync.MaxBackoff.Duration != time.Hour || cfg.UI.MetricsDays != 90 || cfg.UI.StaleBranchDays != 14 {
+		t.Errorf("unexpected defaults %+v, %+v", cfg.Sync, cfg.UI)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tt := range []struct {
+		config string
+		want   []string
+	}{
+		{`{"sync": {"interval": "5x"}}`, []string{`sync.interval: invalid duration "5x"`}},
+		{`{"sources": [{"name": "a", "type": "jira", "url": "https://a"}, {"name": "a", "type": "trac"}]}`, []string{
+			`sources[1].name: duplicate source "a"`,
+			`sources[1].type: unknown type "trac"`,
+			`sources[1].url: missing`,
+		}},
+		{`{"sources": [{"name": "a", "type": "jira", "url": "https://a", "workflow": {"Open": "new"}}]}`, []string{
+			`sources[0].workflow["Open"]: unknown status category "new"`,
+		}},
+		{`{"repos": [{"url": "https://a"}, {}]}`, []string{`repos[1].url: missing`}},
+		{`{"log": {"period": "10s", "maxRecords": -1}}`, []string{
+			`log.maxRecords: must not be negative`,
+			`log.period: must be at least 1m`,
+		}},
+		{`{"ui": {"hotspots": "many"}}`, []string{`ui.hotspots: line 1, column`}},
+		{`{"ui": {"releaseTagPattern": "v{name}["}}`, []string{`ui.releaseTagPattern: invalid pattern "v{name}["`}},
+		{`{"ui": {"color": "red"}}`, []string{`ui.color: line 1, column 15: unknown key`}},
+		{"{\n  \"sources\": [{}, {}, {\"name\": \"a\", \"colour\": \"red\"}]\n}", []string{
+			`sources[2].colour: line 2, column 44: unknown key`,
+		}},
+		{"{\n  \"ui\": {,}\n}", []string{`line 2, column 10`}},
+	} {
+		_, err := Parse([]byte(tt.config))
+		if err == nil {
+			t.Errorf("%s: expected error", tt.config)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error %q should contain %q", tt.config, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashconfig
+
+import (
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/urld/devdashboard"
+)
+
+// NewScheduler returns a scheduler running a syncer for each configured
+// repository and source. Git mirrors are kept below dataDir, unless their
+// directory is configured. NewScheduler returns nil if nothing is
+// configured to sync.
+func (cfg *Config) NewScheduler(dataDir string) *devdashboard.Scheduler {
+	s := new(devdashboard.Scheduler)
+	n := 0
+	for _, r := range cfg.Repos {
+		s.Add(devdashboard.NewGitSyncer(r.URL, r.MirrorDir(dataDir)), r.Interval.Duration, cfg.Sync.Jitter.Duration, cfg.Sync.MaxBackoff.Duration)
+		n++
+	}
+	for _, src := range cfg.Sources {
+		log.Printf("no syncer available for %s source %q, skipping", src.Type, src.Name)
+	}
+	if n == 0 {
+		return nil
+	}
+	return s
+}
+
+// NewLogger returns a logger writing to dataDir, which starts new log
+// files as configured.
+func (cfg *Config) NewLogger(dataDir string) *devdashboard.DiskMutationLogger {
+	l := devdashboard.NewDiskMutationLogger(dataDir)
+	l.Rotation = devdashboard.Rotation{
+		Period:     cfg.Log.Period.Duration,
+		MaxSize:    int64(cfg.Log.MaxSizeMB) << 20,
+		MaxRecords: cfg.Log.MaxRecords,
+	}
+	return l
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// MirrorDir returns the directory of the local mirror of the repository,
+// which is r.Dir or a directory below "git" in the data directory.
+func (r *Repo) MirrorDir(dataDir string) string {
+	if r.Dir != "" {
+		return r.Dir
+	}
+	name := strings.TrimSuffix(r.URL, ".git")
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	name = strings.Trim(unsafeChars.ReplaceAllString(name, "_"), "_") + ".git"
+	return filepath.Join(dataDir, "git", name)
+}
@@ -25,9 +25,9 @@ import (
 // Use Corpus.Update to keep the corpus up-to-date. If you do this, you must
 // hold the read lock if reading and updating concurrently.
 //
-// The initial call to Get will download all the data into a directory
-// "devdashboard" under your operating system's user cache directory.
-// Subsequent calls will only download what's changed since the previous call.
+// Get reads the mutation logs in targetDir, which are written by
+// devdashsync or the devdashboard server. Use GetFromServer to download
+// them from a server instead.
 //
 // For daemons, use Corpus.Update to incrementally update an
 // already-loaded Corpus.
@@ -46,6 +46,20 @@ func Get(ctx context.Context, targetDir string) (*devdashboard.Corpus, error) {
 	return corpus, nil
 }
 
+// GetFromServer is like Get, but downloads the mutation logs served by
+// server (see devdashboard.LogHandler) into targetDir first. Subsequent
+// calls only download what's changed since the previous call.
+//
+// Corpus.Update waits for new mutations on the server.
+func GetFromServer(ctx context.Context, server, targetDir string) (*devdashboard.Corpus, error) {
+	mutSrc := devdashboard.NewNetworkMutationSource(server, targetDir)
+	corpus := new(devdashboard.Corpus)
+	if err := corpus.Initialize(ctx, mutSrc); err != nil {
+		return nil, err
+	}
+	return corpus, nil
+}
+
 // DefaultDir returns the directory containing the cached mutation logs.
 func DefaultDir() string {
 	return filepath.Join(XdgCacheDir(), "devdashboard")
@@ -0,0 +1,231 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashmetrics
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urld/devdashboard"
+)
+
+// Churn is the number of lines changed by a set of commits.
+type Churn struct {
+	Commits int   `json:"commits"`
+	Added   int64 `json:"added"`
+	Deleted int64 `json:"deleted"`
+}
+
+// Lines returns the number of added and deleted lines.
+func (c Churn) Lines() int64 {
+	return c.Added + c.Deleted
+}
+
+// ChurnPeriod is the churn of the commits of one week.
+type ChurnPeriod struct {
+	Start time.Time `json:"start"`
+	Churn
+}
+
+// DirChurn is the churn of a directory.
+type DirChurn struct {
+	Dir string `json:"dir"`
+	Churn
+	Periods []ChurnPeriod `json:"periods"`
+}
+
+// Hotspot is a file which is changed often or by many authors.
+type Hotspot struct {
+	File    string `json:"file"`
+	Authors int    `json:"authors"`
+	Churn
+
+	authors map[string]struct{}
+}
+
+// IssueChurn is the churn of the commits linked to an issue.
+type IssueChurn struct {
+	Issue *devdashboard.Issue `json:"-"`
+	Key   string              `json:"key"`
+	Churn
+}
+
+// ChurnReport holds the churn of all commits of a repository within a window.
+type ChurnReport struct {
+	Window   Window        `json:"window"`
+	Repo     string        `json:"repo"`
+	Project  string        `json:"project,omitempty"`
+	Total    Churn         `json:"total"`
+	Periods  []ChurnPeriod `json:"periods"`
+	Dirs     []*DirChurn   `json:"dirs"`
+	Hotspots []*Hotspot    `json:"hotspots"`
+	Issues   []*IssueChurn `json:"issues"`
+}
+
+// ComputeChurn computes the churn of all commits of the repository which
+// were committed within the window. Churn is aggregated per week, per
+// directory up to the given depth, per file and per linked issue. Hotspots
+// are sorted by the number of commits changing the file, then by the number
+// of authors.
+//
+// If the corpus is updated concurrently, the caller must hold its read lock.
+func ComputeChurn(r *devdashboard.GitRepo, w Window, depth int) *ChurnReport {
+	return computeChurn(r, nil, w, depth)
+}
+
+// ComputeProjectChurn is like ComputeChurn, but only considers the files of
+// the repository which belong to the project, and the project's issues.
+func ComputeProjectChurn(p *devdashboard.Project, r *devdashboard.GitRepo, w Window, depth int) *ChurnReport {
+	report := computeChurn(r, p, w, depth)
+	report.Project = p.ID
+	return report
+}
+
+func computeChurn(r *devdashboard.GitRepo, p *devdashboard.Project, w Window, depth int) *ChurnReport {
+	report := &ChurnReport{Window: w, Repo: r.URL}
+	weeks := weekStarts(w)
+	for _, start := range weeks {
+		report.Periods = append(report.Periods, ChurnPeriod{Start: start})
+	}
+	dirs := make(map[string]*DirChurn)
+	files := make(map[string]*Hotspot)
+	issues := make(map[string]*IssueChurn)
+
+	r.ForeachCommit(func(gc *devdashboard.GitCommit) error {
+		if !w.Contains(gc.CommitTime) || p != nil && !p.ContainsCommit(gc) {
+			return nil
+		}
+		week := weekIndex(weeks, gc.CommitTime)
+		var added, deleted int64
+		dirsSeen := make(map[string]bool)
+		for _, f := range gc.DiffTree {
+			if p != nil && !p.ContainsFile(r, f.File()) {
+				continue
+			}
+			added += f.Added()
+			deleted += f.Deleted()
+			hs, ok := files[f.File()]
+			if !ok {
+				hs = &Hotspot{File: f.File(), authors: make(map[string]struct{})}
+				files[f.File()] = hs
+			}
+			hs.add(f.Added(), f.Deleted())
+			hs.authors[gc.Author.Email()] = struct{}{}
+
+			dir := dirPrefix(f.File(), depth)
+			dc, ok := dirs[dir]
+			if !ok {
+				dc = &DirChurn{Dir: dir, Periods: make([]ChurnPeriod, len(weeks))}
+				for n, start := range weeks {
+					dc.Periods[n].Start = start
+				}
+				dirs[dir] = dc
+			}
+			dc.Added += f.Added()
+			dc.Deleted += f.Deleted()
+			dc.Periods[week].Added += f.Added()
+			dc.Periods[week].Deleted += f.Deleted()
+			if !dirsSeen[dir] {
+				dirsSeen[dir] = true
+				dc.Commits++
+				dc.Periods[week].Commits++
+			}
+		}
+		report.Total.add(added, deleted)
+		report.Periods[week].add(added, deleted)
+
+		for _, i := range gc.Issues() {
+			if p != nil && i.Project() != p {
+				continue
+			}
+			ic, ok := issues[i.ID]
+			if !ok {
+				ic = &IssueChurn{Issue: i, Key: i.IssueKey}
+				issues[i.ID] = ic
+			}
+			ic.add(added, deleted)
+		}
+		return nil
+	})
+
+	for _, dc := range dirs {
+		report.Dirs = append(report.Dirs, dc)
+	}
+	sort.Slice(report.Dirs, func(i, j int) bool {
+		a, b := report.Dirs[i], report.Dirs[j]
+		if a.Lines() != b.Lines() {
+			return a.Lines() > b.Lines()
+		}
+		return a.Dir < b.Dir
+	})
+	for _, hs := range files {
+		hs.Authors = len(hs.authors)
+		report.Hotspots = append(report.Hotspots, hs)
+	}
+	sort.Slice(report.Hotspots, func(i, j int) bool {
+		a, b := report.Hotspots[i], report.Hotspots[j]
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		if a.Authors != b.Authors {
+			return a.Authors > b.Authors
+		}
+		return a.File < b.File
+	})
+	for _, ic := range issues {
+		report.Issues = append(report.Issues, ic)
+	}
+	sort.Slice(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Lines() != b.Lines() {
+			return a.Lines() > b.Lines()
+		}
+		return a.Key < b.Key
+	})
+	return report
+}
+
+func (c *Churn) add(added, deleted int64) {
+	c.Commits++
+	c.Added += added
+	c.Deleted += deleted
+}
+
+// weekStarts returns the start of every week within the window. The first
+// week starts at the beginning of the window, all others on Monday.
+func weekStarts(w Window) []time.Time {
+	starts := []time.Time{w.Start}
+	y, m, d := w.Start.Date()
+	t := time.Date(y, m, d, 0, 0, 0, 0, w.Start.Location())
+	t = t.AddDate(0, 0, (8-int(t.Weekday()))%7)
+	if !t.After(w.Start) {
+		t = t.AddDate(0, 0, 7)
+	}
+	for ; t.Before(w.End); t = t.AddDate(0, 0, 7) {
+		starts = append(starts, t)
+	}
+	return starts
+}
+
+// weekIndex returns the index of the week containing t.
+func weekIndex(weeks []time.Time, t time.Time) int {
+	return sort.Search(len(weeks), func(i int) bool { return weeks[i].After(t) }) - 1
+}
+
+// dirPrefix returns the directory of the file truncated to depth elements,
+// or "." for files in the root directory.
+func dirPrefix(file string, depth int) string {
+	dir := path.Dir(file)
+	if dir == "." || depth <= 0 {
+		return "."
+	}
+	elems := strings.Split(dir, "/")
+	if len(elems) > depth {
+		elems = elems[:depth]
+	}
+	return strings.Join(elems, "/")
+}
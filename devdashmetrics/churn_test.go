@@ -0,0 +1,159 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashmetrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/urld/devdashboard"
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// testSource is a MutationSource of a fixed list of mutations.
+type testSource []*devdashpb.Mutation
+
+func (src testSource) GetMutations(ctx context.Context) <-chan devdashboard.MutationStreamEvent {
+	ch := make(chan devdashboard.MutationStreamEvent, len(src)+1)
+	for _, m := range src {
+		ch <- devdashboard.MutationStreamEvent{Mutation: m}
+	}
+	ch <- devdashboard.MutationStreamEvent{End: true}
+	return ch
+}
+
+// testCommit returns a commit mutation committed at t by author, changing
+// files given as name, added and deleted lines.
+func testCommit(sha1, author string, t time.Time, msg string, files ...interface{}) *devdashpb.Mutation {
+	raw := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"
+	raw += fmt.Sprintf("author %s <%s@example.com> %d +0000\n", author, author, t.Unix())
+	raw += fmt.Sprintf("committer %s <%s@example.com> %d +0000\n", author, author, t.Unix())
+	raw += "\n" + msg + "\n"
+	tree := new(devdashpb.GitDiffTree)
+	for n := 0; n < len(files); n += 3 {
+		tree.File = append(tree.File, &devdashpb.GitDiffTreeFile{
+			File: files[n].(string), Added: int64(files[n+1].(int)), Deleted: int64(files[n+2].(int)),
+		})
+	}
+	return &devdashpb.Mutation{Git: &devdashpb.GitMutation{
+		Repo:   "https://example.com/abc.git",
+		Commit: &devdashpb.GitCommit{Sha1: sha1, Raw: raw, DiffTree: tree},
+	}}
+}
+
+func TestComputeChurn(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2019, 1, d, 10, 0, 0, 0, time.UTC) }
+	c := new(devdashboard.Corpus)
+	err := c.Initialize(context.Background(), testSource{
+		{Issue: &devdashpb.IssueMutation{Id: "i1", Project: "ABC", IssueKey: "ABC-1"}},
+		{Issue: &devdashpb.IssueMutation{Id: "i2", Project: "ABC", IssueKey: "ABC-2"}},
+		testCommit("aaaa0", "alice", day(0), "ABC-1: before the window", "cmd/a/main.go", 100, 0),
+		testCommit("aaaa1", "alice", day(1), "ABC-1: setup", "README.md", 10, 0, "cmd/a/main.go", 20, 0),
+		testCommit("bbbb2", "bob", day(8), "ABC-1: fix", "cmd/a/main.go", 5, 3),
+		testCommit("cccc3", "alice", day(9), "ABC-2: docs", "README.md", 2, 1, "docs/x.md", 4, 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the window starts on Tuesday, so the second week starts on January 7:
+	w := Window{Start: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC)}
+	report := ComputeChurn(c.GitRepo("abc"), w, 1)
+
+	if want := (Churn{Commits: 3, Added: 41, Deleted: 4}); report.Total != want {
+		t.Errorf("total churn %+v, want %+v", report.Total, want)
+	}
+	wantPeriods := []Churn{{1, 30, 0}, {2, 11, 4}, {0, 0, 0}}
+	if len(report.Periods) != len(wantPeriods) {
+		t.Fatalf("got %d periods, want %d", len(report.Periods), len(wantPeriods))
+	}
+	for n, want := range wantPeriods {
+		if report.Periods[n].Churn != want {
+			t.Errorf("period %d: churn %+v, want %+v", n, report.Periods[n].Churn, want)
+		}
+	}
+
+	wantDirs := []string{"cmd 2 28", ". 2 13", "docs 1 4"}
+	if len(report.Dirs) != len(wantDirs) {
+		t.Fatalf("got %d dirs, want %d", len(report.Dirs), len(wantDirs))
+	}
+	for n, want := range wantDirs {
+		dc := report.Dirs[n]
+		if got := fmt.Sprintf("%s %d %d", dc.Dir, dc.Commits, dc.Lines()); got != want {
+			t.Errorf("dir %d: %s, want %s", n, got, want)
+		}
+	}
+
+	// file, commits, authors:
+	wantHotspots := []string{"cmd/a/main.go 2 2", "README.md 2 1", "docs/x.md 1 1"}
+	if len(report.Hotspots) != len(wantHotspots) {
+		t.Fatalf("got %d hotspots, want %d", len(report.Hotspots), len(wantHotspots))
+	}
+	for n, want := range wantHotspots {
+		hs := report.Hotspots[n]
+		if got := fmt.Sprintf("%s %d %d", hs.File, hs.Commits, hs.Authors); got != want {
+			t.Errorf("hotspot %d: %s, want %s", n, got, want)
+		}
+	}
+
+	// key, commits, added, deleted:
+	wantIssues := []string{"ABC-1 2 35 3", "ABC-2 1 6 1"}
+	if len(report.Issues) != len(wantIssues) {
+		t.Fatalf("got %d issues, want %d", len(report.Issues), len(wantIssues))
+	}
+	for n, want := range wantIssues {
+		ic := report.Issues[n]
+		if got := fmt.Sprintf("%s %d %d %d", ic.Key, ic.Commits, ic.Added, ic.Deleted); got != want {
+			t.Errorf("issue %d: %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestWeekStarts(t *testing.T) {
+	w := Window{
+		Start: time.Date(2019, 1, 2, 12, 0, 0, 0, time.UTC), // Wednesday
+		End:   time.Date(2019, 1, 21, 0, 0, 0, 0, time.UTC),
+	}
+	weeks := weekStarts(w)
+	want := []time.Time{
+		w.Start,
+		time.Date(2019, 1, 7, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, 1, 14, 0, 0, 0, 0, time.UTC),
+	}
+	if len(weeks) != len(want) {
+		t.Fatalf("weekStarts() = %v, want %v", weeks, want)
+	}
+	for n := range want {
+		if !weeks[n].Equal(want[n]) {
+			t.Errorf("week %d starts %v, want %v", n, weeks[n], want[n])
+		}
+	}
+	if n := weekIndex(weeks, time.Date(2019, 1, 13, 23, 0, 0, 0, time.UTC)); n != 1 {
+		t.Errorf("weekIndex() = %d, want 1", n)
+	}
+	if n := weekIndex(weeks, w.Start); n != 0 {
+		t.Errorf("weekIndex() = %d, want 0", n)
+	}
+}
+
+func TestDirPrefix(t *testing.T) {
+	for _, tt := range []struct {
+		file  string
+		depth int
+		want  string
+	}{
+		{"README.md", 1, "."},
+		{"cmd/devdashboard/main.go", 1, "cmd"},
+		{"cmd/devdashboard/main.go", 2, "cmd/devdashboard"},
+		{"cmd/devdashboard/main.go", 3, "cmd/devdashboard"},
+		{"cmd/devdashboard/main.go", 0, "."},
+	} {
+		if got := dirPrefix(tt.file, tt.depth); got != tt.want {
+			t.Errorf("dirPrefix(%q, %d) = %q, want %q", tt.file, tt.depth, got, tt.want)
+		}
+	}
+}
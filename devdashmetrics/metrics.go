@@ -0,0 +1,312 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package devdashmetrics computes flow metrics like lead time, cycle time
+// and time in status from the issues and commits of a corpus, as well as
+// code churn and hotspots from the diff trees of git commits.
+package devdashmetrics
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/urld/devdashboard"
+)
+
+// Window is the time range of issues considered for a report. An issue is
+// part of the window if it was closed within [Start, End).
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// LastDays returns a window which covers the last n days until now.
+func LastDays(n int) Window {
+	end := time.Now()
+	return Window{Start: end.AddDate(0, 0, -n), End: end}
+}
+
+// Contains reports whether t is within the window.
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// Distribution summarizes a set of durations.
+type Distribution struct {
+	Count int
+	Mean  time.Duration
+	P50   time.Duration
+	P75   time.Duration
+	P90   time.Duration
+	P95   time.Duration
+	Max   time.Duration
+}
+
+// NewDistribution computes the distribution of the given durations.
+func NewDistribution(durations []time.Duration) Distribution {
+	if len(durations) == 0 {
+		return Distribution{}
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	return Distribution{
+		Count: len(sorted),
+		Mean:  sum / time.Duration(len(sorted)),
+		P50:   percentile(sorted, 50),
+		P75:   percentile(sorted, 75),
+		P90:   percentile(sorted, 90),
+		P95:   percentile(sorted, 95),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the p-th percentile of the sorted durations using the
+// nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// MarshalJSON encodes all durations of the distribution in seconds.
+func (d Distribution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Count int     `json:"count"`
+		Mean  float64 `json:"mean"`
+		P50   float64 `json:"p50"`
+		P75   float64 `json:"p75"`
+		P90   float64 `json:"p90"`
+		P95   float64 `json:"p95"`
+		Max   float64 `json:"max"`
+	}{
+		Count: d.Count,
+		Mean:  d.Mean.Seconds(),
+		P50:   d.P50.Seconds(),
+		P75:   d.P75.Seconds(),
+		P90:   d.P90.Seconds(),
+		P95:   d.P95.Seconds(),
+		Max:   d.Max.Seconds(),
+	})
+}
+
+// Metrics holds the flow metrics of a group of issues, such as all issues of
+// a project.
+type Metrics struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	LeadTime     Distribution            `json:"leadTime"`
+	CycleTime    Distribution            `json:"cycleTime"`
+	TimeInStatus map[string]Distribution `json:"timeInStatus"`
+
+	leadTimes     []time.Duration
+	cycleTimes    []time.Duration
+	statusTimes   map[string][]time.Duration
+	issuesCounted map[string]struct{}
+}
+
+func newMetrics(id, name string) *Metrics {
+	return &Metrics{
+		ID:            id,
+		Name:          name,
+		statusTimes:   make(map[string][]time.Duration),
+		issuesCounted: make(map[string]struct{}),
+	}
+}
+
+func (m *Metrics) add(i *devdashboard.Issue) {
+	if _, ok := m.issuesCounted[i.ID]; ok {
+		return
+	}
+	m.issuesCounted[i.ID] = struct{}{}
+	if d, ok := LeadTime(i); ok {
+		m.leadTimes = append(m.leadTimes, d)
+	}
+	if d, ok := CycleTime(i); ok {
+		m.cycleTimes = append(m.cycleTimes, d)
+	}
+	for status, d := range TimeInStatus(i) {
+		m.statusTimes[status] = append(m.statusTimes[status], d)
+	}
+}
+
+func (m *Metrics) finish() {
+	m.LeadTime = NewDistribution(m.leadTimes)
+	m.CycleTime = NewDistribution(m.cycleTimes)
+	m.TimeInStatus = make(map[string]Distribution, len(m.statusTimes))
+	for status, durations := range m.statusTimes {
+		m.TimeInStatus[status] = NewDistribution(durations)
+	}
+}
+
+// Report holds the flow metrics of all closed issues within a window,
+// grouped by project, milestone, label, issue type and priority.
+type Report struct {
+	Window     Window     `json:"window"`
+	Projects   []*Metrics `json:"projects"`
+	Milestones []*Metrics `json:"milestones"`
+	Labels     []*Metrics `json:"labels"`
+	Types      []*Metrics `json:"types"`
+	Priorities []*Metrics `json:"priorities"`
+}
+
+// Compute computes the flow metrics of all issues closed within the window.
+//
+// If the corpus is updated concurrently, the caller must hold its read lock.
+func Compute(c *devdashboard.Corpus, w Window) *Report {
+	projects := make(map[string]*Metrics)
+	milestones := make(map[string]*Metrics)
+	labels := make(map[string]*Metrics)
+	types := make(map[string]*Metrics)
+	priorities := make(map[string]*Metrics)
+	for _, i := range c.Issues {
+		if !i.Closed || !w.Contains(i.ClosedAt) {
+			continue
+		}
+		if p := i.Project(); p != nil {
+			m, ok := projects[p.ID]
+			if !ok {
+				m = newMetrics(p.ID, p.Name)
+				projects[p.ID] = m
+			}
+			m.add(i)
+		}
+		for id, ms := range i.Milestones {
+			m, ok := milestones[id]
+			if !ok {
+				name := ms.Name
+				if p := ms.Project(); p != nil {
+					name = p.Name + ": " + ms.Name
+				}
+				m = newMetrics(id, name)
+				milestones[id] = m
+			}
+			m.add(i)
+		}
+		for l := range i.Labels {
+			m, ok := labels[l]
+			if !ok {
+				m = newMetrics(l, l)
+				labels[l] = m
+			}
+			m.add(i)
+		}
+		if i.Type != "" {
+			m, ok := types[i.Type]
+			if !ok {
+				m = newMetrics(i.Type, i.Type)
+				types[i.Type] = m
+			}
+			m.add(i)
+		}
+		if i.Priority != "" {
+			m, ok := priorities[i.Priority]
+			if !ok {
+				m = newMetrics(i.Priority, i.Priority)
+				priorities[i.Priority] = m
+			}
+			m.add(i)
+		}
+	}
+	return &Report{
+		Window:     w,
+		Projects:   sortedMetrics(projects),
+		Milestones: sortedMetrics(milestones),
+		Labels:     sortedMetrics(labels),
+		Types:      sortedMetrics(types),
+		Priorities: sortedMetrics(priorities),
+	}
+}
+
+func sortedMetrics(m map[string]*Metrics) []*Metrics {
+	ret := make([]*Metrics, 0, len(m))
+	for _, metrics := range m {
+		metrics.finish()
+		ret = append(ret, metrics)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// LeadTime returns the time from creation until the issue was closed.
+// It returns false if the issue is not closed.
+func LeadTime(i *devdashboard.Issue) (time.Duration, bool) {
+	if !i.Closed || i.Created.IsZero() || i.ClosedAt.IsZero() {
+		return 0, false
+	}
+	return i.ClosedAt.Sub(i.Created), true
+}
+
+// CycleTime returns the time from the start of work until the issue was
+// closed. It returns false if the issue is not closed or if there is no
+// indication when work on the issue started.
+func CycleTime(i *devdashboard.Issue) (time.Duration, bool) {
+	if !i.Closed || i.ClosedAt.IsZero() {
+		return 0, false
+	}
+	start, ok := WorkStarted(i)
+	if !ok {
+		return 0, false
+	}
+	return i.ClosedAt.Sub(start), true
+}
+
+// WorkStarted returns the time work on the issue started. If the project
+// defines a workflow, this is the first time the issue entered an in
+// progress status. Otherwise it is the first change of the issue's initial
+// status. The author time of the first linked commit is used instead, if
+// it came earlier.
+func WorkStarted(i *devdashboard.Issue) (time.Time, bool) {
+	var start time.Time
+	if p := i.Project(); p != nil && len(p.Workflow) > 0 {
+		for _, sc := range i.StatusHistory {
+			if !sc.Time.IsZero() && p.StatusCategory(sc.Status) == devdashboard.StatusInProgress {
+				start = sc.Time
+				break
+			}
+		}
+	} else if len(i.StatusHistory) > 1 {
+		start = i.StatusHistory[1].Time
+	}
+	if gc := i.FirstCommit(); gc != nil && !gc.AuthorTime.IsZero() {
+		if start.IsZero() || gc.AuthorTime.Before(start) {
+			start = gc.AuthorTime
+		}
+	}
+	return start, !start.IsZero()
+}
+
+// TimeInStatus returns the total time the issue spent in each status.
+// The time in the last status is measured until the issue was closed, or
+// until now for open issues.
+func TimeInStatus(i *devdashboard.Issue) map[string]time.Duration {
+	ret := make(map[string]time.Duration)
+	for n, sc := range i.StatusHistory {
+		if sc.Time.IsZero() {
+			continue
+		}
+		var end time.Time
+		switch {
+		case n+1 < len(i.StatusHistory):
+			end = i.StatusHistory[n+1].Time
+		case i.Closed:
+			end = i.ClosedAt
+		default:
+			end = time.Now()
+		}
+		if end.After(sc.Time) {
+			ret[sc.Status] += end.Sub(sc.Time)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,189 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashmetrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/urld/devdashboard"
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestNewDistribution(t *testing.T) {
+	var durations []time.Duration
+	for i := 10; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Hour)
+	}
+	d := NewDistribution(durations)
+	want := Distribution{
+		Count: 10,
+		Mean:  5*time.Hour + 30*time.Minute,
+		P50:   5 * time.Hour,
+		P75:   8 * time.Hour,
+		P90:   9 * time.Hour,
+		P95:   10 * time.Hour,
+		Max:   10 * time.Hour,
+	}
+	if d != want {
+		t.Errorf("NewDistribution() = %+v, want %+v", d, want)
+	}
+
+	if d := NewDistribution(nil); d != (Distribution{}) {
+		t.Errorf("NewDistribution(nil) = %+v, want zero value", d)
+	}
+}
+
+// metricsCorpus returns a corpus with the issues:
+//
+//	ABC-1: New, In Progress, Review, Done, with a commit before work started
+//	ABC-2: New, In Progress, Done
+//	ABC-3: New, still open
+//	ABC-4: Done after the window
+//	DEF-1: Open, Fixed, in a project without workflow
+func metricsCorpus(t *testing.T) *devdashboard.Corpus {
+	ts := func(d int) *timestamp.Timestamp {
+		pt, err := ptypes.TimestampProto(day(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pt
+	}
+	closed := &devdashpb.BoolChange{Val: true}
+	c := new(devdashboard.Corpus)
+	err := c.Initialize(context.Background(), testSource{
+		{Project: &devdashpb.ProjectMutation{
+			Id:         "ABC",
+			Name:       "Alpha",
+			Milestones: []*devdashpb.TrackerMilestone{{Id: "m1", Project: "ABC", Name: "v1"}},
+			Workflow: &devdashpb.Workflow{Statuses: []*devdashpb.WorkflowStatus{
+				{Name: "New", Category: devdashpb.StatusCategory_TODO},
+				{Name: "In Progress", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Review", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Done", Category: devdashpb.StatusCategory_DONE},
+			}},
+		}},
+		{Project: &devdashpb.ProjectMutation{Id: "DEF", Name: "Delta"}},
+
+		{Issue: &devdashpb.IssueMutation{
+			Id: "i1", Project: "ABC", IssueKey: "ABC-1", Created: ts(1), Status: "New",
+			Milestones: []*devdashpb.TrackerMilestone{{Id: "m1"}},
+			Labels:     []*devdashpb.TrackerLabel{{Name: "bug"}},
+		}},
+		{Issue: &devdashpb.IssueMutation{Id: "i1", Status: "In Progress", Updated: ts(3)}},
+		{Issue: &devdashpb.IssueMutation{Id: "i1", Status: "Review", Updated: ts(4)}},
+		{Issue: &devdashpb.IssueMutation{Id: "i1", Status: "Done", Closed: closed, ClosedAt: ts(6)}},
+		testCommit("aaaa1", "alice", day(2), "ABC-1: fix"),
+
+		{Issue: &devdashpb.IssueMutation{
+			Id: "i2", Project: "ABC", IssueKey: "ABC-2", Created: ts(1), Status: "New",
+			Labels: []*devdashpb.TrackerLabel{{Name: "bug"}, {Name: "ui"}},
+		}},
+		{Issue: &devdashpb.IssueMutation{Id: "i2", Status: "In Progress", Updated: ts(2)}},
+		{Issue: &devdashpb.IssueMutation{Id: "i2", Status: "Done", Updated: ts(5), Closed: closed, ClosedAt: ts(5)}},
+
+		{Issue: &devdashpb.IssueMutation{Id: "i3", Project: "ABC", IssueKey: "ABC-3", Created: ts(1), Status: "New"}},
+
+		{Issue: &devdashpb.IssueMutation{
+			Id: "i4", Project: "ABC", IssueKey: "ABC-4", Created: ts(1), Status: "New",
+			Milestones: []*devdashpb.TrackerMilestone{{Id: "m1"}},
+			Labels:     []*devdashpb.TrackerLabel{{Name: "bug"}},
+		}},
+		{Issue: &devdashpb.IssueMutation{Id: "i4", Status: "Done", Updated: ts(20), Closed: closed, ClosedAt: ts(20)}},
+
+		{Issue: &devdashpb.IssueMutation{Id: "i5", Project: "DEF", IssueKey: "DEF-1", Created: ts(1), Status: "Open"}},
+		{Issue: &devdashpb.IssueMutation{Id: "i5", Status: "Fixed", Updated: ts(3)}},
+		{Issue: &devdashpb.IssueMutation{Id: "i5", Closed: closed, ClosedAt: ts(4)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func day(d int) time.Time {
+	return time.Date(2019, 1, d, 9, 0, 0, 0, time.UTC)
+}
+
+func TestIssueMetrics(t *testing.T) {
+	c := metricsCorpus(t)
+	const d = 24 * time.Hour
+	for _, tt := range []struct {
+		id           string
+		leadTime     time.Duration // 0 if not closed
+		cycleTime    time.Duration // 0 if not closed or not started
+		workStarted  time.Time
+		timeInStatus map[string]time.Duration
+	}{
+		{"i1", 5 * d, 4 * d, day(2), map[string]time.Duration{"New": 2 * d, "In Progress": d, "Review": 2 * d}},
+		{"i2", 4 * d, 3 * d, day(2), map[string]time.Duration{"New": d, "In Progress": 3 * d}},
+		{"i3", 0, 0, time.Time{}, nil},
+		{"i5", 3 * d, d, day(3), map[string]time.Duration{"Open": 2 * d, "Fixed": d}},
+	} {
+		i := c.Issues[tt.id]
+		if lt, ok := LeadTime(i); lt != tt.leadTime || ok != (tt.leadTime != 0) {
+			t.Errorf("%s: LeadTime() = %v, %v, want %v", tt.id, lt, ok, tt.leadTime)
+		}
+		if ct, ok := CycleTime(i); ct != tt.cycleTime || ok != (tt.cycleTime != 0) {
+			t.Errorf("%s: CycleTime() = %v, %v, want %v", tt.id, ct, ok, tt.cycleTime)
+		}
+		if ws, ok := WorkStarted(i); !ws.Equal(tt.workStarted) || ok != !tt.workStarted.IsZero() {
+			t.Errorf("%s: WorkStarted() = %v, %v, want %v", tt.id, ws, ok, tt.workStarted)
+		}
+		if tt.timeInStatus == nil {
+			continue
+		}
+		got := TimeInStatus(i)
+		if len(got) != len(tt.timeInStatus) {
+			t.Errorf("%s: TimeInStatus() = %v, want %v", tt.id, got, tt.timeInStatus)
+		}
+		for status, want := range tt.timeInStatus {
+			if got[status] != want {
+				t.Errorf("%s: TimeInStatus() = %v, want %v", tt.id, got, tt.timeInStatus)
+			}
+		}
+	}
+
+	// open issues are in their last status until now:
+	if got := TimeInStatus(c.Issues["i3"]); got["New"] < time.Since(day(1))-time.Minute {
+		t.Errorf("i3: TimeInStatus() = %v, want New since %v", got, day(1))
+	}
+}
+
+func TestCompute(t *testing.T) {
+	c := metricsCorpus(t)
+	report := Compute(c, Window{Start: day(1), End: day(10)})
+
+	const d = 24 * time.Hour
+	for _, tt := range []struct {
+		group   string
+		metrics []*Metrics
+		want    []string // id, name, issue count and mean lead time
+	}{
+		{"projects", report.Projects, []string{"ABC Alpha 2 108h0m0s", "DEF Delta 1 72h0m0s"}},
+		{"milestones", report.Milestones, []string{"m1 Alpha: v1 1 120h0m0s"}},
+		{"labels", report.Labels, []string{"bug bug 2 108h0m0s", "ui ui 1 96h0m0s"}},
+	} {
+		var got []string
+		for _, m := range tt.metrics {
+			got = append(got, fmt.Sprintf("%s %s %d %v", m.ID, m.Name, m.LeadTime.Count, m.LeadTime.Mean))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.group, got, tt.want)
+		}
+	}
+
+	abc := report.Projects[0]
+	if abc.CycleTime.Count != 2 || abc.CycleTime.Max != 4*d {
+		t.Errorf("unexpected cycle time of ABC: %+v", abc.CycleTime)
+	}
+	if s := abc.TimeInStatus["In Progress"]; s.Count != 2 || s.Mean != 2*d {
+		t.Errorf("unexpected time in progress of ABC: %+v", s)
+	}
+}
@@ -21,11 +21,105 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type StatusCategory int32
+
+const (
+	StatusCategory_UNKNOWN     StatusCategory = 0
+	StatusCategory_TODO        StatusCategory = 1
+	StatusCategory_IN_PROGRESS StatusCategory = 2
+	StatusCategory_DONE        StatusCategory = 3
+)
+
+var StatusCategory_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "TODO",
+	2: "IN_PROGRESS",
+	3: "DONE",
+}
+
+var StatusCategory_value = map[string]int32{
+	"UNKNOWN":     0,
+	"TODO":        1,
+	"IN_PROGRESS": 2,
+	"DONE":        3,
+}
+
+func (x StatusCategory) String() string {
+	return proto.EnumName(StatusCategory_name, int32(x))
+}
+
+func (StatusCategory) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{0}
+}
+
+type SprintState int32
+
+const (
+	SprintState_UNSPECIFIED SprintState = 0
+	SprintState_FUTURE      SprintState = 1
+	SprintState_ACTIVE      SprintState = 2
+	SprintState_CLOSED      SprintState = 3
+)
+
+var SprintState_name = map[int32]string{
+	0: "UNSPECIFIED",
+	1: "FUTURE",
+	2: "ACTIVE",
+	3: "CLOSED",
+}
+
+var SprintState_value = map[string]int32{
+	"UNSPECIFIED": 0,
+	"FUTURE":      1,
+	"ACTIVE":      2,
+	"CLOSED":      3,
+}
+
+func (x SprintState) String() string {
+	return proto.EnumName(SprintState_name, int32(x))
+}
+
+func (SprintState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{1}
+}
+
+type IssueLinkType int32
+
+const (
+	IssueLinkType_RELATES    IssueLinkType = 0
+	IssueLinkType_BLOCKS     IssueLinkType = 1
+	IssueLinkType_DUPLICATES IssueLinkType = 2
+	IssueLinkType_PARENT     IssueLinkType = 3
+)
+
+var IssueLinkType_name = map[int32]string{
+	0: "RELATES",
+	1: "BLOCKS",
+	2: "DUPLICATES",
+	3: "PARENT",
+}
+
+var IssueLinkType_value = map[string]int32{
+	"RELATES":    0,
+	"BLOCKS":     1,
+	"DUPLICATES": 2,
+	"PARENT":     3,
+}
+
+func (x IssueLinkType) String() string {
+	return proto.EnumName(IssueLinkType_name, int32(x))
+}
+
+func (IssueLinkType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{2}
+}
+
 type Mutation struct {
 	Project              *ProjectMutation `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
 	Release              *ReleaseMutation `protobuf:"bytes,2,opt,name=release,proto3" json:"release,omitempty"`
 	Issue                *IssueMutation   `protobuf:"bytes,3,opt,name=issue,proto3" json:"issue,omitempty"`
 	Git                  *GitMutation     `protobuf:"bytes,4,opt,name=git,proto3" json:"git,omitempty"`
+	Sprint               *SprintMutation  `protobuf:"bytes,5,opt,name=sprint,proto3" json:"sprint,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -84,15 +178,28 @@ func (m *Mutation) GetGit() *GitMutation {
 	return nil
 }
 
+func (m *Mutation) GetSprint() *SprintMutation {
+	if m != nil {
+		return m.Sprint
+	}
+	return nil
+}
+
 type ProjectMutation struct {
-	Id                   string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string              `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description          string              `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Milestones           []*TrackerMilestone `protobuf:"bytes,4,rep,name=milestones,proto3" json:"milestones,omitempty"`
-	DeletedMilestones    []string            `protobuf:"bytes,5,rep,name=deleted_milestones,json=deletedMilestones,proto3" json:"deleted_milestones,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Id                string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string              `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description       string              `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Milestones        []*TrackerMilestone `protobuf:"bytes,4,rep,name=milestones,proto3" json:"milestones,omitempty"`
+	DeletedMilestones []string            `protobuf:"bytes,5,rep,name=deleted_milestones,json=deletedMilestones,proto3" json:"deleted_milestones,omitempty"`
+	// workflow replaces the project's workflow if set.
+	Workflow             *Workflow      `protobuf:"bytes,6,opt,name=workflow,proto3" json:"workflow,omitempty"`
+	Labels               []*Label       `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty"`
+	DeletedLabels        []string       `protobuf:"bytes,8,rep,name=deleted_labels,json=deletedLabels,proto3" json:"deleted_labels,omitempty"`
+	Repos                []*ProjectRepo `protobuf:"bytes,9,rep,name=repos,proto3" json:"repos,omitempty"`
+	DeletedRepos         []string       `protobuf:"bytes,10,rep,name=deleted_repos,json=deletedRepos,proto3" json:"deleted_repos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *ProjectMutation) Reset()         { *m = ProjectMutation{} }
@@ -155,25 +262,266 @@ func (m *ProjectMutation) GetDeletedMilestones() []string {
 	return nil
 }
 
+func (m *ProjectMutation) GetWorkflow() *Workflow {
+	if m != nil {
+		return m.Workflow
+	}
+	return nil
+}
+
+func (m *ProjectMutation) GetLabels() []*Label {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *ProjectMutation) GetDeletedLabels() []string {
+	if m != nil {
+		return m.DeletedLabels
+	}
+	return nil
+}
+
+func (m *ProjectMutation) GetRepos() []*ProjectRepo {
+	if m != nil {
+		return m.Repos
+	}
+	return nil
+}
+
+func (m *ProjectMutation) GetDeletedRepos() []string {
+	if m != nil {
+		return m.DeletedRepos
+	}
+	return nil
+}
+
+// ProjectRepo is a git repository holding the code of a project.
+type ProjectRepo struct {
+	Repo string `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	// paths replaces the directories within the repository which belong to
+	// the project. The whole repository belongs to the project if empty.
+	Paths                []string `protobuf:"bytes,2,rep,name=paths,proto3" json:"paths,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProjectRepo) Reset()         { *m = ProjectRepo{} }
+func (m *ProjectRepo) String() string { return proto.CompactTextString(m) }
+func (*ProjectRepo) ProtoMessage()    {}
+func (*ProjectRepo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{2}
+}
+
+func (m *ProjectRepo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProjectRepo.Unmarshal(m, b)
+}
+func (m *ProjectRepo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProjectRepo.Marshal(b, m, deterministic)
+}
+func (m *ProjectRepo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProjectRepo.Merge(m, src)
+}
+func (m *ProjectRepo) XXX_Size() int {
+	return xxx_messageInfo_ProjectRepo.Size(m)
+}
+func (m *ProjectRepo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProjectRepo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProjectRepo proto.InternalMessageInfo
+
+func (m *ProjectRepo) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *ProjectRepo) GetPaths() []string {
+	if m != nil {
+		return m.Paths
+	}
+	return nil
+}
+
+// Label is a label which can be assigned to the issues of a project.
+// Issues refer to labels by name.
+type Label struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color                string   `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	Description          string   `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Label) Reset()         { *m = Label{} }
+func (m *Label) String() string { return proto.CompactTextString(m) }
+func (*Label) ProtoMessage()    {}
+func (*Label) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{3}
+}
+
+func (m *Label) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Label.Unmarshal(m, b)
+}
+func (m *Label) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Label.Marshal(b, m, deterministic)
+}
+func (m *Label) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Label.Merge(m, src)
+}
+func (m *Label) XXX_Size() int {
+	return xxx_messageInfo_Label.Size(m)
+}
+func (m *Label) XXX_DiscardUnknown() {
+	xxx_messageInfo_Label.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Label proto.InternalMessageInfo
+
+func (m *Label) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Label) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Label) GetColor() string {
+	if m != nil {
+		return m.Color
+	}
+	return ""
+}
+
+func (m *Label) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+// Workflow is the ordered list of statuses an issue of a project
+// passes through.
+type Workflow struct {
+	Statuses             []*WorkflowStatus `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Workflow) Reset()         { *m = Workflow{} }
+func (m *Workflow) String() string { return proto.CompactTextString(m) }
+func (*Workflow) ProtoMessage()    {}
+func (*Workflow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{4}
+}
+
+func (m *Workflow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Workflow.Unmarshal(m, b)
+}
+func (m *Workflow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Workflow.Marshal(b, m, deterministic)
+}
+func (m *Workflow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Workflow.Merge(m, src)
+}
+func (m *Workflow) XXX_Size() int {
+	return xxx_messageInfo_Workflow.Size(m)
+}
+func (m *Workflow) XXX_DiscardUnknown() {
+	xxx_messageInfo_Workflow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Workflow proto.InternalMessageInfo
+
+func (m *Workflow) GetStatuses() []*WorkflowStatus {
+	if m != nil {
+		return m.Statuses
+	}
+	return nil
+}
+
+type WorkflowStatus struct {
+	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Category             StatusCategory `protobuf:"varint,2,opt,name=category,proto3,enum=devdashpb.StatusCategory" json:"category,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *WorkflowStatus) Reset()         { *m = WorkflowStatus{} }
+func (m *WorkflowStatus) String() string { return proto.CompactTextString(m) }
+func (*WorkflowStatus) ProtoMessage()    {}
+func (*WorkflowStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{5}
+}
+
+func (m *WorkflowStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WorkflowStatus.Unmarshal(m, b)
+}
+func (m *WorkflowStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WorkflowStatus.Marshal(b, m, deterministic)
+}
+func (m *WorkflowStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WorkflowStatus.Merge(m, src)
+}
+func (m *WorkflowStatus) XXX_Size() int {
+	return xxx_messageInfo_WorkflowStatus.Size(m)
+}
+func (m *WorkflowStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_WorkflowStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WorkflowStatus proto.InternalMessageInfo
+
+func (m *WorkflowStatus) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WorkflowStatus) GetCategory() StatusCategory {
+	if m != nil {
+		return m.Category
+	}
+	return StatusCategory_UNKNOWN
+}
+
 type ReleaseMutation struct {
-	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description          string               `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	FreezeDate           *timestamp.Timestamp `protobuf:"bytes,4,opt,name=freeze_date,json=freezeDate,proto3" json:"freeze_date,omitempty"`
-	ReleaseDate          *timestamp.Timestamp `protobuf:"bytes,5,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
-	Closed               *BoolChange          `protobuf:"bytes,6,opt,name=closed,proto3" json:"closed,omitempty"`
-	Milestones           []*TrackerMilestone  `protobuf:"bytes,7,rep,name=milestones,proto3" json:"milestones,omitempty"`
-	DeletedMilestones    []string             `protobuf:"bytes,8,rep,name=deleted_milestones,json=deletedMilestones,proto3" json:"deleted_milestones,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Id                string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description       string               `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	FreezeDate        *timestamp.Timestamp `protobuf:"bytes,4,opt,name=freeze_date,json=freezeDate,proto3" json:"freeze_date,omitempty"`
+	ReleaseDate       *timestamp.Timestamp `protobuf:"bytes,5,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	Closed            *BoolChange          `protobuf:"bytes,6,opt,name=closed,proto3" json:"closed,omitempty"`
+	Milestones        []*TrackerMilestone  `protobuf:"bytes,7,rep,name=milestones,proto3" json:"milestones,omitempty"`
+	DeletedMilestones []string             `protobuf:"bytes,8,rep,name=deleted_milestones,json=deletedMilestones,proto3" json:"deleted_milestones,omitempty"`
+	// tag_pattern is a glob pattern, such as "v2019.01*", matching the git
+	// tags of the release.
+	TagPattern           string   `protobuf:"bytes,9,opt,name=tag_pattern,json=tagPattern,proto3" json:"tag_pattern,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ReleaseMutation) Reset()         { *m = ReleaseMutation{} }
 func (m *ReleaseMutation) String() string { return proto.CompactTextString(m) }
 func (*ReleaseMutation) ProtoMessage()    {}
 func (*ReleaseMutation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{2}
+	return fileDescriptor_f8eddb5bdebb5405, []int{6}
 }
 
 func (m *ReleaseMutation) XXX_Unmarshal(b []byte) error {
@@ -250,6 +598,181 @@ func (m *ReleaseMutation) GetDeletedMilestones() []string {
 	return nil
 }
 
+func (m *ReleaseMutation) GetTagPattern() string {
+	if m != nil {
+		return m.TagPattern
+	}
+	return ""
+}
+
+// SprintMutation is a time boxed iteration of a project or board, such as
+// a Jira sprint or a GitLab iteration.
+type SprintMutation struct {
+	Id                   string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Project              string               `protobuf:"bytes,2,opt,name=project,proto3" json:"project,omitempty"`
+	Board                string               `protobuf:"bytes,3,opt,name=board,proto3" json:"board,omitempty"`
+	Name                 string               `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Goal                 string               `protobuf:"bytes,5,opt,name=goal,proto3" json:"goal,omitempty"`
+	StartDate            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate              *timestamp.Timestamp `protobuf:"bytes,7,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	CompleteDate         *timestamp.Timestamp `protobuf:"bytes,8,opt,name=complete_date,json=completeDate,proto3" json:"complete_date,omitempty"`
+	State                SprintState          `protobuf:"varint,9,opt,name=state,proto3,enum=devdashpb.SprintState" json:"state,omitempty"`
+	Issues               []*SprintIssue       `protobuf:"bytes,10,rep,name=issues,proto3" json:"issues,omitempty"`
+	DeletedIssues        []string             `protobuf:"bytes,11,rep,name=deleted_issues,json=deletedIssues,proto3" json:"deleted_issues,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SprintMutation) Reset()         { *m = SprintMutation{} }
+func (m *SprintMutation) String() string { return proto.CompactTextString(m) }
+func (*SprintMutation) ProtoMessage()    {}
+func (*SprintMutation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{7}
+}
+
+func (m *SprintMutation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SprintMutation.Unmarshal(m, b)
+}
+func (m *SprintMutation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SprintMutation.Marshal(b, m, deterministic)
+}
+func (m *SprintMutation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SprintMutation.Merge(m, src)
+}
+func (m *SprintMutation) XXX_Size() int {
+	return xxx_messageInfo_SprintMutation.Size(m)
+}
+func (m *SprintMutation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SprintMutation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SprintMutation proto.InternalMessageInfo
+
+func (m *SprintMutation) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SprintMutation) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
+}
+
+func (m *SprintMutation) GetBoard() string {
+	if m != nil {
+		return m.Board
+	}
+	return ""
+}
+
+func (m *SprintMutation) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SprintMutation) GetGoal() string {
+	if m != nil {
+		return m.Goal
+	}
+	return ""
+}
+
+func (m *SprintMutation) GetStartDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartDate
+	}
+	return nil
+}
+
+func (m *SprintMutation) GetEndDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndDate
+	}
+	return nil
+}
+
+func (m *SprintMutation) GetCompleteDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.CompleteDate
+	}
+	return nil
+}
+
+func (m *SprintMutation) GetState() SprintState {
+	if m != nil {
+		return m.State
+	}
+	return SprintState_UNSPECIFIED
+}
+
+func (m *SprintMutation) GetIssues() []*SprintIssue {
+	if m != nil {
+		return m.Issues
+	}
+	return nil
+}
+
+func (m *SprintMutation) GetDeletedIssues() []string {
+	if m != nil {
+		return m.DeletedIssues
+	}
+	return nil
+}
+
+type SprintIssue struct {
+	Issue                string               `protobuf:"bytes,1,opt,name=issue,proto3" json:"issue,omitempty"`
+	Added                *timestamp.Timestamp `protobuf:"bytes,2,opt,name=added,proto3" json:"added,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SprintIssue) Reset()         { *m = SprintIssue{} }
+func (m *SprintIssue) String() string { return proto.CompactTextString(m) }
+func (*SprintIssue) ProtoMessage()    {}
+func (*SprintIssue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{8}
+}
+
+func (m *SprintIssue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SprintIssue.Unmarshal(m, b)
+}
+func (m *SprintIssue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SprintIssue.Marshal(b, m, deterministic)
+}
+func (m *SprintIssue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SprintIssue.Merge(m, src)
+}
+func (m *SprintIssue) XXX_Size() int {
+	return xxx_messageInfo_SprintIssue.Size(m)
+}
+func (m *SprintIssue) XXX_DiscardUnknown() {
+	xxx_messageInfo_SprintIssue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SprintIssue proto.InternalMessageInfo
+
+func (m *SprintIssue) GetIssue() string {
+	if m != nil {
+		return m.Issue
+	}
+	return ""
+}
+
+func (m *SprintIssue) GetAdded() *timestamp.Timestamp {
+	if m != nil {
+		return m.Added
+	}
+	return nil
+}
+
 type IssueMutation struct {
 	Project  string `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
 	Id       string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
@@ -275,6 +798,14 @@ type IssueMutation struct {
 	Labels               []*TrackerLabel      `protobuf:"bytes,18,rep,name=labels,proto3" json:"labels,omitempty"`
 	DeletedLabels        []string             `protobuf:"bytes,19,rep,name=deleted_labels,json=deletedLabels,proto3" json:"deleted_labels,omitempty"`
 	Url                  string               `protobuf:"bytes,20,opt,name=url,proto3" json:"url,omitempty"`
+	Links                []*IssueLink         `protobuf:"bytes,21,rep,name=links,proto3" json:"links,omitempty"`
+	DeletedLinks         []*IssueLink         `protobuf:"bytes,22,rep,name=deleted_links,json=deletedLinks,proto3" json:"deleted_links,omitempty"`
+	Priority             string               `protobuf:"bytes,23,opt,name=priority,proto3" json:"priority,omitempty"`
+	Type                 string               `protobuf:"bytes,24,opt,name=type,proto3" json:"type,omitempty"`
+	StoryPoints          *DoubleChange        `protobuf:"bytes,25,opt,name=story_points,json=storyPoints,proto3" json:"story_points,omitempty"`
+	Estimate             *Int64Change         `protobuf:"bytes,26,opt,name=estimate,proto3" json:"estimate,omitempty"`
+	CustomFields         []*CustomField       `protobuf:"bytes,27,rep,name=custom_fields,json=customFields,proto3" json:"custom_fields,omitempty"`
+	DeletedCustomFields  []string             `protobuf:"bytes,28,rep,name=deleted_custom_fields,json=deletedCustomFields,proto3" json:"deleted_custom_fields,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -284,7 +815,7 @@ func (m *IssueMutation) Reset()         { *m = IssueMutation{} }
 func (m *IssueMutation) String() string { return proto.CompactTextString(m) }
 func (*IssueMutation) ProtoMessage()    {}
 func (*IssueMutation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{3}
+	return fileDescriptor_f8eddb5bdebb5405, []int{9}
 }
 
 func (m *IssueMutation) XXX_Unmarshal(b []byte) error {
@@ -445,6 +976,224 @@ func (m *IssueMutation) GetUrl() string {
 	return ""
 }
 
+func (m *IssueMutation) GetLinks() []*IssueLink {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
+func (m *IssueMutation) GetDeletedLinks() []*IssueLink {
+	if m != nil {
+		return m.DeletedLinks
+	}
+	return nil
+}
+
+func (m *IssueMutation) GetPriority() string {
+	if m != nil {
+		return m.Priority
+	}
+	return ""
+}
+
+func (m *IssueMutation) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *IssueMutation) GetStoryPoints() *DoubleChange {
+	if m != nil {
+		return m.StoryPoints
+	}
+	return nil
+}
+
+func (m *IssueMutation) GetEstimate() *Int64Change {
+	if m != nil {
+		return m.Estimate
+	}
+	return nil
+}
+
+func (m *IssueMutation) GetCustomFields() []*CustomField {
+	if m != nil {
+		return m.CustomFields
+	}
+	return nil
+}
+
+func (m *IssueMutation) GetDeletedCustomFields() []string {
+	if m != nil {
+		return m.DeletedCustomFields
+	}
+	return nil
+}
+
+// CustomField is a tracker specific field of an issue.
+type CustomField struct {
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//	*CustomField_StringVal
+	//	*CustomField_NumberVal
+	//	*CustomField_BoolVal
+	//	*CustomField_TimeVal
+	Value                isCustomField_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *CustomField) Reset()         { *m = CustomField{} }
+func (m *CustomField) String() string { return proto.CompactTextString(m) }
+func (*CustomField) ProtoMessage()    {}
+func (*CustomField) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{10}
+}
+
+func (m *CustomField) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomField.Unmarshal(m, b)
+}
+func (m *CustomField) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomField.Marshal(b, m, deterministic)
+}
+func (m *CustomField) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomField.Merge(m, src)
+}
+func (m *CustomField) XXX_Size() int {
+	return xxx_messageInfo_CustomField.Size(m)
+}
+func (m *CustomField) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomField.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomField proto.InternalMessageInfo
+
+type isCustomField_Value interface {
+	isCustomField_Value()
+}
+
+type CustomField_StringVal struct {
+	StringVal string `protobuf:"bytes,2,opt,name=string_val,json=stringVal,proto3,oneof" json:"string_val,omitempty"`
+}
+type CustomField_NumberVal struct {
+	NumberVal float64 `protobuf:"fixed64,3,opt,name=number_val,json=numberVal,proto3,oneof" json:"number_val,omitempty"`
+}
+type CustomField_BoolVal struct {
+	BoolVal bool `protobuf:"varint,4,opt,name=bool_val,json=boolVal,proto3,oneof" json:"bool_val,omitempty"`
+}
+type CustomField_TimeVal struct {
+	TimeVal *timestamp.Timestamp `protobuf:"bytes,5,opt,name=time_val,json=timeVal,proto3,oneof" json:"time_val,omitempty"`
+}
+
+func (*CustomField_StringVal) isCustomField_Value() {}
+func (*CustomField_NumberVal) isCustomField_Value() {}
+func (*CustomField_BoolVal) isCustomField_Value()   {}
+func (*CustomField_TimeVal) isCustomField_Value()   {}
+
+func (m *CustomField) GetValue() isCustomField_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *CustomField) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *CustomField) GetStringVal() string {
+	if x, ok := m.GetValue().(*CustomField_StringVal); ok {
+		return x.StringVal
+	}
+	return ""
+}
+
+func (m *CustomField) GetNumberVal() float64 {
+	if x, ok := m.GetValue().(*CustomField_NumberVal); ok {
+		return x.NumberVal
+	}
+	return 0
+}
+
+func (m *CustomField) GetBoolVal() bool {
+	if x, ok := m.GetValue().(*CustomField_BoolVal); ok {
+		return x.BoolVal
+	}
+	return false
+}
+
+func (m *CustomField) GetTimeVal() *timestamp.Timestamp {
+	if x, ok := m.GetValue().(*CustomField_TimeVal); ok {
+		return x.TimeVal
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*CustomField) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*CustomField_StringVal)(nil),
+		(*CustomField_NumberVal)(nil),
+		(*CustomField_BoolVal)(nil),
+		(*CustomField_TimeVal)(nil),
+	}
+}
+
+// IssueLink is a relation from the issue of the IssueMutation to
+// another issue.
+type IssueLink struct {
+	Issue                string        `protobuf:"bytes,1,opt,name=issue,proto3" json:"issue,omitempty"`
+	Type                 IssueLinkType `protobuf:"varint,2,opt,name=type,proto3,enum=devdashpb.IssueLinkType" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *IssueLink) Reset()         { *m = IssueLink{} }
+func (m *IssueLink) String() string { return proto.CompactTextString(m) }
+func (*IssueLink) ProtoMessage()    {}
+func (*IssueLink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{11}
+}
+
+func (m *IssueLink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IssueLink.Unmarshal(m, b)
+}
+func (m *IssueLink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IssueLink.Marshal(b, m, deterministic)
+}
+func (m *IssueLink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IssueLink.Merge(m, src)
+}
+func (m *IssueLink) XXX_Size() int {
+	return xxx_messageInfo_IssueLink.Size(m)
+}
+func (m *IssueLink) XXX_DiscardUnknown() {
+	xxx_messageInfo_IssueLink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IssueLink proto.InternalMessageInfo
+
+func (m *IssueLink) GetIssue() string {
+	if m != nil {
+		return m.Issue
+	}
+	return ""
+}
+
+func (m *IssueLink) GetType() IssueLinkType {
+	if m != nil {
+		return m.Type
+	}
+	return IssueLinkType_RELATES
+}
+
 type TrackerLabel struct {
 	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -456,7 +1205,7 @@ func (m *TrackerLabel) Reset()         { *m = TrackerLabel{} }
 func (m *TrackerLabel) String() string { return proto.CompactTextString(m) }
 func (*TrackerLabel) ProtoMessage()    {}
 func (*TrackerLabel) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{4}
+	return fileDescriptor_f8eddb5bdebb5405, []int{12}
 }
 
 func (m *TrackerLabel) XXX_Unmarshal(b []byte) error {
@@ -500,7 +1249,7 @@ func (m *TrackerMilestone) Reset()         { *m = TrackerMilestone{} }
 func (m *TrackerMilestone) String() string { return proto.CompactTextString(m) }
 func (*TrackerMilestone) ProtoMessage()    {}
 func (*TrackerMilestone) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{5}
+	return fileDescriptor_f8eddb5bdebb5405, []int{13}
 }
 
 func (m *TrackerMilestone) XXX_Unmarshal(b []byte) error {
@@ -571,7 +1320,7 @@ func (m *IssueCommentMutation) Reset()         { *m = IssueCommentMutation{} }
 func (m *IssueCommentMutation) String() string { return proto.CompactTextString(m) }
 func (*IssueCommentMutation) ProtoMessage()    {}
 func (*IssueCommentMutation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{6}
+	return fileDescriptor_f8eddb5bdebb5405, []int{14}
 }
 
 func (m *IssueCommentMutation) XXX_Unmarshal(b []byte) error {
@@ -640,7 +1389,7 @@ func (m *TrackerUser) Reset()         { *m = TrackerUser{} }
 func (m *TrackerUser) String() string { return proto.CompactTextString(m) }
 func (*TrackerUser) ProtoMessage()    {}
 func (*TrackerUser) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{7}
+	return fileDescriptor_f8eddb5bdebb5405, []int{15}
 }
 
 func (m *TrackerUser) XXX_Unmarshal(b []byte) error {
@@ -696,7 +1445,7 @@ func (m *GitMutation) Reset()         { *m = GitMutation{} }
 func (m *GitMutation) String() string { return proto.CompactTextString(m) }
 func (*GitMutation) ProtoMessage()    {}
 func (*GitMutation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{8}
+	return fileDescriptor_f8eddb5bdebb5405, []int{16}
 }
 
 func (m *GitMutation) XXX_Unmarshal(b []byte) error {
@@ -759,7 +1508,7 @@ func (m *GitCommit) Reset()         { *m = GitCommit{} }
 func (m *GitCommit) String() string { return proto.CompactTextString(m) }
 func (*GitCommit) ProtoMessage()    {}
 func (*GitCommit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{9}
+	return fileDescriptor_f8eddb5bdebb5405, []int{17}
 }
 
 func (m *GitCommit) XXX_Unmarshal(b []byte) error {
@@ -813,7 +1562,7 @@ func (m *GitDiffTree) Reset()         { *m = GitDiffTree{} }
 func (m *GitDiffTree) String() string { return proto.CompactTextString(m) }
 func (*GitDiffTree) ProtoMessage()    {}
 func (*GitDiffTree) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{10}
+	return fileDescriptor_f8eddb5bdebb5405, []int{18}
 }
 
 func (m *GitDiffTree) XXX_Unmarshal(b []byte) error {
@@ -856,7 +1605,7 @@ func (m *GitDiffTreeFile) Reset()         { *m = GitDiffTreeFile{} }
 func (m *GitDiffTreeFile) String() string { return proto.CompactTextString(m) }
 func (*GitDiffTreeFile) ProtoMessage()    {}
 func (*GitDiffTreeFile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{11}
+	return fileDescriptor_f8eddb5bdebb5405, []int{19}
 }
 
 func (m *GitDiffTreeFile) XXX_Unmarshal(b []byte) error {
@@ -924,7 +1673,7 @@ func (m *GitRef) Reset()         { *m = GitRef{} }
 func (m *GitRef) String() string { return proto.CompactTextString(m) }
 func (*GitRef) ProtoMessage()    {}
 func (*GitRef) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{12}
+	return fileDescriptor_f8eddb5bdebb5405, []int{20}
 }
 
 func (m *GitRef) XXX_Unmarshal(b []byte) error {
@@ -970,7 +1719,7 @@ func (m *BoolChange) Reset()         { *m = BoolChange{} }
 func (m *BoolChange) String() string { return proto.CompactTextString(m) }
 func (*BoolChange) ProtoMessage()    {}
 func (*BoolChange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f8eddb5bdebb5405, []int{13}
+	return fileDescriptor_f8eddb5bdebb5405, []int{21}
 }
 
 func (m *BoolChange) XXX_Unmarshal(b []byte) error {
@@ -998,11 +1747,100 @@ func (m *BoolChange) GetVal() bool {
 	return false
 }
 
+type DoubleChange struct {
+	Val                  float64  `protobuf:"fixed64,1,opt,name=val,proto3" json:"val,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DoubleChange) Reset()         { *m = DoubleChange{} }
+func (m *DoubleChange) String() string { return proto.CompactTextString(m) }
+func (*DoubleChange) ProtoMessage()    {}
+func (*DoubleChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{22}
+}
+
+func (m *DoubleChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DoubleChange.Unmarshal(m, b)
+}
+func (m *DoubleChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DoubleChange.Marshal(b, m, deterministic)
+}
+func (m *DoubleChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DoubleChange.Merge(m, src)
+}
+func (m *DoubleChange) XXX_Size() int {
+	return xxx_messageInfo_DoubleChange.Size(m)
+}
+func (m *DoubleChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_DoubleChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DoubleChange proto.InternalMessageInfo
+
+func (m *DoubleChange) GetVal() float64 {
+	if m != nil {
+		return m.Val
+	}
+	return 0
+}
+
+type Int64Change struct {
+	Val                  int64    `protobuf:"varint,1,opt,name=val,proto3" json:"val,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Int64Change) Reset()         { *m = Int64Change{} }
+func (m *Int64Change) String() string { return proto.CompactTextString(m) }
+func (*Int64Change) ProtoMessage()    {}
+func (*Int64Change) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f8eddb5bdebb5405, []int{23}
+}
+
+func (m *Int64Change) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Int64Change.Unmarshal(m, b)
+}
+func (m *Int64Change) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Int64Change.Marshal(b, m, deterministic)
+}
+func (m *Int64Change) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Int64Change.Merge(m, src)
+}
+func (m *Int64Change) XXX_Size() int {
+	return xxx_messageInfo_Int64Change.Size(m)
+}
+func (m *Int64Change) XXX_DiscardUnknown() {
+	xxx_messageInfo_Int64Change.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Int64Change proto.InternalMessageInfo
+
+func (m *Int64Change) GetVal() int64 {
+	if m != nil {
+		return m.Val
+	}
+	return 0
+}
+
 func init() {
+	proto.RegisterEnum("devdashpb.StatusCategory", StatusCategory_name, StatusCategory_value)
+	proto.RegisterEnum("devdashpb.SprintState", SprintState_name, SprintState_value)
+	proto.RegisterEnum("devdashpb.IssueLinkType", IssueLinkType_name, IssueLinkType_value)
 	proto.RegisterType((*Mutation)(nil), "devdashpb.Mutation")
 	proto.RegisterType((*ProjectMutation)(nil), "devdashpb.ProjectMutation")
+	proto.RegisterType((*ProjectRepo)(nil), "devdashpb.ProjectRepo")
+	proto.RegisterType((*Label)(nil), "devdashpb.Label")
+	proto.RegisterType((*Workflow)(nil), "devdashpb.Workflow")
+	proto.RegisterType((*WorkflowStatus)(nil), "devdashpb.WorkflowStatus")
 	proto.RegisterType((*ReleaseMutation)(nil), "devdashpb.ReleaseMutation")
+	proto.RegisterType((*SprintMutation)(nil), "devdashpb.SprintMutation")
+	proto.RegisterType((*SprintIssue)(nil), "devdashpb.SprintIssue")
 	proto.RegisterType((*IssueMutation)(nil), "devdashpb.IssueMutation")
+	proto.RegisterType((*CustomField)(nil), "devdashpb.CustomField")
+	proto.RegisterType((*IssueLink)(nil), "devdashpb.IssueLink")
 	proto.RegisterType((*TrackerLabel)(nil), "devdashpb.TrackerLabel")
 	proto.RegisterType((*TrackerMilestone)(nil), "devdashpb.TrackerMilestone")
 	proto.RegisterType((*IssueCommentMutation)(nil), "devdashpb.IssueCommentMutation")
@@ -1013,70 +1851,121 @@ func init() {
 	proto.RegisterType((*GitDiffTreeFile)(nil), "devdashpb.GitDiffTreeFile")
 	proto.RegisterType((*GitRef)(nil), "devdashpb.GitRef")
 	proto.RegisterType((*BoolChange)(nil), "devdashpb.BoolChange")
+	proto.RegisterType((*DoubleChange)(nil), "devdashpb.DoubleChange")
+	proto.RegisterType((*Int64Change)(nil), "devdashpb.Int64Change")
 }
 
 func init() { proto.RegisterFile("devdash.proto", fileDescriptor_f8eddb5bdebb5405) }
 
 var fileDescriptor_f8eddb5bdebb5405 = []byte{
-	// 956 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xdd, 0x8a, 0x1b, 0x47,
-	0x13, 0x45, 0x1a, 0x49, 0xab, 0x29, 0xed, 0x6f, 0x7f, 0xfb, 0x39, 0x8d, 0x0c, 0x61, 0x33, 0x60,
-	0x58, 0x12, 0x7b, 0x44, 0x6c, 0x43, 0x2e, 0x8c, 0x2f, 0xec, 0x75, 0x62, 0x42, 0x6c, 0x08, 0xcd,
-	0xe6, 0x5a, 0xb4, 0x34, 0x35, 0xda, 0x4e, 0x66, 0xa6, 0xc5, 0x74, 0xcb, 0x8e, 0xf2, 0x26, 0xb9,
-	0x0c, 0xe4, 0x4d, 0xf2, 0x02, 0x21, 0x0f, 0x92, 0x67, 0x08, 0xfd, 0x33, 0xa3, 0x91, 0xb4, 0xca,
-	0x8a, 0xb0, 0x77, 0xd5, 0x53, 0xe7, 0xb4, 0xaa, 0x4f, 0x9d, 0xea, 0x16, 0x1c, 0x25, 0xf8, 0x21,
-	0xe1, 0xea, 0x26, 0x9e, 0x97, 0x52, 0x4b, 0x12, 0xfa, 0xe5, 0x7c, 0x32, 0x7c, 0x31, 0x13, 0xfa,
-	0x66, 0x31, 0x89, 0xa7, 0x32, 0x1f, 0xcd, 0x64, 0xc6, 0x8b, 0xd9, 0xc8, 0x62, 0x26, 0x8b, 0x74,
-	0x34, 0xd7, 0xcb, 0x39, 0xaa, 0x91, 0x16, 0x39, 0x2a, 0xcd, 0xf3, 0xf9, 0x2a, 0x72, 0xfb, 0x44,
-	0x7f, 0xb6, 0xa0, 0xff, 0x7e, 0xa1, 0xb9, 0x16, 0xb2, 0x20, 0xcf, 0xe1, 0x60, 0x5e, 0xca, 0x1f,
-	0x71, 0xaa, 0x69, 0xeb, 0xa2, 0x75, 0x39, 0x78, 0x3a, 0x8c, 0xeb, 0x9f, 0x89, 0xbf, 0x77, 0x99,
-	0x0a, 0xcc, 0x2a, 0xa8, 0x61, 0x95, 0x98, 0x21, 0x57, 0x48, 0xdb, 0x5b, 0x2c, 0xe6, 0x32, 0x2b,
-	0x96, 0x87, 0x92, 0x18, 0xba, 0x42, 0xa9, 0x05, 0xd2, 0xc0, 0x72, 0x68, 0x83, 0xf3, 0xad, 0xf9,
-	0x5e, 0x33, 0x1c, 0x8c, 0x5c, 0x42, 0x30, 0x13, 0x9a, 0x76, 0x2c, 0xfa, 0x41, 0x03, 0xfd, 0x56,
-	0xac, 0x6a, 0x32, 0x90, 0xe8, 0x8f, 0x16, 0x9c, 0x6c, 0x14, 0x4b, 0x8e, 0xa1, 0x2d, 0x12, 0x7b,
-	0xa8, 0x90, 0xb5, 0x45, 0x42, 0x08, 0x74, 0x0a, 0x9e, 0xbb, 0x82, 0x43, 0x66, 0x63, 0x72, 0x01,
-	0x83, 0x04, 0xd5, 0xb4, 0x14, 0x73, 0x43, 0xb1, 0x75, 0x85, 0xac, 0xf9, 0x89, 0xbc, 0x00, 0xc8,
-	0x45, 0x86, 0x4a, 0xcb, 0x02, 0x15, 0xed, 0x5c, 0x04, 0x97, 0x83, 0xa7, 0x0f, 0x1b, 0xa5, 0x5c,
-	0x97, 0x7c, 0xfa, 0x13, 0x96, 0xef, 0x2b, 0x0c, 0x6b, 0xc0, 0xc9, 0x13, 0x20, 0x09, 0x66, 0xa8,
-	0x31, 0x19, 0x37, 0x36, 0xe9, 0x5e, 0x04, 0x97, 0x21, 0x3b, 0xf3, 0x99, 0x9a, 0xa9, 0xa2, 0xbf,
-	0xdb, 0x70, 0xb2, 0x21, 0xde, 0xbd, 0x9d, 0x62, 0x90, 0x96, 0x88, 0xbf, 0xe0, 0x38, 0xe1, 0x1a,
-	0xbd, 0xa2, 0xc3, 0x78, 0x26, 0xe5, 0x2c, 0xc3, 0xb8, 0xb2, 0x4e, 0x7c, 0x5d, 0x39, 0x85, 0x81,
-	0x83, 0xbf, 0xe1, 0x1a, 0xc9, 0x4b, 0x38, 0xf4, 0x1d, 0x74, 0xec, 0xee, 0x9d, 0xec, 0x81, 0xc7,
-	0x5b, 0xfa, 0x13, 0xe8, 0x4d, 0x33, 0xa9, 0x30, 0xa1, 0x3d, 0x4b, 0xfc, 0x7f, 0x43, 0xbd, 0xd7,
-	0x52, 0x66, 0x57, 0x37, 0xbc, 0x98, 0x21, 0xf3, 0xa0, 0x0d, 0xc1, 0x0f, 0xee, 0x43, 0xf0, 0xfe,
-	0x2e, 0xc1, 0x7f, 0xef, 0xc1, 0xd1, 0x9a, 0xf3, 0x08, 0x5d, 0x1f, 0x87, 0x70, 0x65, 0x79, 0xd7,
-	0x88, 0x76, 0xdd, 0x88, 0x21, 0xf4, 0xad, 0x4b, 0xbf, 0xc3, 0xa5, 0x57, 0xbc, 0x5e, 0x93, 0x87,
-	0x10, 0x16, 0x52, 0x8f, 0xf1, 0x67, 0xa1, 0x9c, 0x7d, 0xfb, 0xac, 0x5f, 0x48, 0xfd, 0xb5, 0x59,
-	0x9b, 0xd9, 0x99, 0x96, 0xc8, 0x35, 0x26, 0x7b, 0x28, 0x59, 0x41, 0x0d, 0x6b, 0x31, 0x4f, 0x2c,
-	0xab, 0x77, 0x37, 0xcb, 0x43, 0xc9, 0x39, 0x74, 0xb5, 0xd0, 0x19, 0xd2, 0x03, 0x5b, 0xa1, 0x5b,
-	0x18, 0x0f, 0x4d, 0x64, 0xb2, 0xa4, 0x7d, 0xe7, 0x21, 0x13, 0x93, 0xc7, 0xd0, 0x95, 0x1f, 0x0b,
-	0x2c, 0x69, 0xb8, 0x35, 0x6d, 0x5e, 0xf1, 0x1f, 0x14, 0x96, 0xcc, 0x81, 0xc8, 0x73, 0x08, 0xb9,
-	0x52, 0x62, 0x56, 0x20, 0x2a, 0x0a, 0xb6, 0x47, 0xbb, 0x18, 0x2b, 0x20, 0xf9, 0x02, 0xaa, 0x1e,
-	0x8c, 0x57, 0xec, 0x81, 0x6d, 0xce, 0xa9, 0x4f, 0xbc, 0xaa, 0xc1, 0xeb, 0x3e, 0x38, 0xbc, 0x0f,
-	0x1f, 0x1c, 0xed, 0xf0, 0x01, 0x79, 0x00, 0x3d, 0xa5, 0xb9, 0x5e, 0x28, 0x7a, 0x6c, 0x25, 0xf1,
-	0xab, 0x86, 0x75, 0x4f, 0xf6, 0xb1, 0xee, 0x57, 0x10, 0xba, 0x68, 0xcc, 0x35, 0x3d, 0xbd, 0xb3,
-	0x4b, 0x7d, 0x07, 0x7e, 0xa5, 0xc9, 0xb3, 0x9a, 0x38, 0x59, 0xd2, 0xb3, 0x7f, 0x6d, 0x80, 0x27,
-	0xbd, 0x5e, 0x92, 0x11, 0xf4, 0x32, 0x3e, 0xc1, 0x4c, 0x51, 0x62, 0xc5, 0xf9, 0x64, 0x9b, 0xf1,
-	0xce, 0xe4, 0x99, 0x87, 0x91, 0x47, 0x70, 0x5c, 0x89, 0xe2, 0x89, 0xff, 0xb3, 0x82, 0x1c, 0xf9,
-	0xaf, 0xef, 0x1c, 0xec, 0x14, 0x82, 0x45, 0x99, 0xd1, 0x73, 0xab, 0x84, 0x09, 0xa3, 0x08, 0x0e,
-	0x9b, 0x1b, 0xde, 0x76, 0x07, 0x45, 0xbf, 0xb5, 0xe0, 0x74, 0xb3, 0x25, 0x5b, 0x97, 0x57, 0x63,
-	0xba, 0xda, 0xeb, 0xd3, 0xb5, 0x52, 0x3a, 0xd8, 0x47, 0xe9, 0xaa, 0x82, 0xce, 0xee, 0x5b, 0xb0,
-	0xbb, 0x75, 0x0b, 0x46, 0x7f, 0xb5, 0xe0, 0xdc, 0x8e, 0xfb, 0x95, 0xcc, 0x73, 0x2c, 0x6e, 0x7b,
-	0x2a, 0x02, 0x5b, 0xe7, 0xe7, 0xd0, 0x59, 0x28, 0x2c, 0xfd, 0xdb, 0xb6, 0xab, 0x15, 0x16, 0x53,
-	0x0f, 0x53, 0xd0, 0x18, 0xa6, 0xc6, 0x88, 0x77, 0xfe, 0xd3, 0x88, 0x77, 0xf7, 0x1e, 0xf1, 0xe8,
-	0x2d, 0x0c, 0x1a, 0x45, 0xed, 0xf5, 0x5e, 0x9c, 0x43, 0x17, 0x73, 0x2e, 0x32, 0x5f, 0xb3, 0x5b,
-	0x44, 0xbf, 0xb6, 0x60, 0xd0, 0x78, 0x58, 0x0d, 0xb3, 0xc4, 0xb9, 0xf4, 0x7b, 0xd9, 0x98, 0x3c,
-	0x86, 0xde, 0x54, 0xe6, 0xb9, 0xd0, 0x5e, 0x9a, 0xf3, 0xf5, 0x47, 0xf9, 0xca, 0xe6, 0x98, 0xc7,
-	0x90, 0x47, 0x66, 0x87, 0x54, 0xd1, 0xc0, 0xfa, 0xf3, 0x6c, 0x1d, 0xcb, 0x30, 0x65, 0x36, 0x4d,
-	0x3e, 0x83, 0xc3, 0xca, 0x97, 0x16, 0xde, 0xb1, 0xae, 0x1c, 0xf8, 0x6f, 0x0c, 0x53, 0x15, 0xa5,
-	0x10, 0xd6, 0xdb, 0x9b, 0xc2, 0xd4, 0x0d, 0xff, 0xb2, 0x2a, 0xcc, 0xc4, 0xc6, 0xb4, 0x25, 0xff,
-	0xe8, 0x4f, 0x69, 0x42, 0x33, 0x53, 0x89, 0x48, 0xd3, 0xb1, 0x2e, 0xb1, 0xfa, 0xc3, 0xb1, 0xf1,
-	0x17, 0xe2, 0x8d, 0x48, 0xd3, 0xeb, 0x12, 0x91, 0xf5, 0x13, 0x1f, 0x45, 0x2f, 0xad, 0x04, 0x55,
-	0x82, 0xc4, 0xd0, 0x49, 0x45, 0x86, 0xb4, 0x65, 0x0f, 0x30, 0xbc, 0x9d, 0xfe, 0x8d, 0xc8, 0x90,
-	0x59, 0x5c, 0x94, 0xc3, 0xc9, 0x46, 0xc2, 0x14, 0xeb, 0xb7, 0xb0, 0xc5, 0x9a, 0xd8, 0xe8, 0xcf,
-	0x93, 0x04, 0xdd, 0x6b, 0x12, 0x30, 0xb7, 0x30, 0xc3, 0xe1, 0x8f, 0x6c, 0xcb, 0x0d, 0x58, 0xb5,
-	0x34, 0xd7, 0xd3, 0x44, 0x14, 0xbc, 0x5c, 0xfa, 0xb7, 0xc4, 0xaf, 0xa2, 0x18, 0x7a, 0x4e, 0x48,
-	0x7b, 0x7c, 0x4c, 0xfd, 0x8f, 0x98, 0xb0, 0x16, 0xa9, 0xbd, 0x12, 0x29, 0xfa, 0x14, 0x60, 0x35,
-	0x4b, 0x86, 0xf3, 0x81, 0x67, 0x96, 0xd3, 0x67, 0x26, 0x9c, 0xf4, 0xac, 0xcf, 0x9e, 0xfd, 0x13,
-	0x00, 0x00, 0xff, 0xff, 0xf2, 0x55, 0x36, 0xc1, 0x78, 0x0a, 0x00, 0x00,
+	// 1737 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xdd, 0x6e, 0xdb, 0xc8,
+	0x15, 0x8e, 0x44, 0x49, 0x26, 0x0f, 0xfd, 0xa3, 0x4c, 0x9c, 0x2c, 0xd7, 0x29, 0x1a, 0x97, 0xc5,
+	0x02, 0x86, 0x9b, 0x95, 0xbb, 0xd9, 0xa4, 0xc1, 0x36, 0x58, 0x14, 0xb6, 0xa4, 0x64, 0x8d, 0x38,
+	0xb6, 0x31, 0x92, 0xb3, 0x17, 0xbd, 0x10, 0x28, 0x71, 0x24, 0xb3, 0xa6, 0x38, 0x02, 0x67, 0x94,
+	0x54, 0xbd, 0xed, 0x03, 0xf4, 0xb6, 0x05, 0x7a, 0xd3, 0x37, 0xe8, 0x23, 0xf4, 0x19, 0xfa, 0x44,
+	0xc5, 0x9c, 0x19, 0x52, 0x94, 0xac, 0xac, 0xdc, 0x45, 0xee, 0xce, 0xf0, 0x7c, 0xdf, 0xf0, 0xcc,
+	0xf9, 0x9b, 0x33, 0xb0, 0x15, 0xb2, 0x0f, 0x61, 0x20, 0xae, 0x1b, 0x93, 0x94, 0x4b, 0x4e, 0x1c,
+	0xb3, 0x9c, 0xf4, 0xf7, 0x5e, 0x8d, 0x22, 0x79, 0x3d, 0xed, 0x37, 0x06, 0x7c, 0x7c, 0x34, 0xe2,
+	0x71, 0x90, 0x8c, 0x8e, 0x10, 0xd3, 0x9f, 0x0e, 0x8f, 0x26, 0x72, 0x36, 0x61, 0xe2, 0x48, 0x46,
+	0x63, 0x26, 0x64, 0x30, 0x9e, 0xcc, 0x25, 0xbd, 0x8f, 0xff, 0xd7, 0x32, 0xd8, 0xef, 0xa6, 0x32,
+	0x90, 0x11, 0x4f, 0xc8, 0x73, 0xd8, 0x98, 0xa4, 0xfc, 0x4f, 0x6c, 0x20, 0xbd, 0xd2, 0x7e, 0xe9,
+	0xc0, 0x7d, 0xb6, 0xd7, 0xc8, 0x7f, 0xd3, 0xb8, 0xd4, 0x9a, 0x0c, 0x4c, 0x33, 0xa8, 0x62, 0xa5,
+	0x2c, 0x66, 0x81, 0x60, 0x5e, 0xf9, 0x16, 0x8b, 0x6a, 0xcd, 0x9c, 0x65, 0xa0, 0xa4, 0x01, 0xd5,
+	0x48, 0x88, 0x29, 0xf3, 0x2c, 0xe4, 0x78, 0x05, 0xce, 0xa9, 0xfa, 0x9e, 0x33, 0x34, 0x8c, 0x1c,
+	0x80, 0x35, 0x8a, 0xa4, 0x57, 0x41, 0xf4, 0xa3, 0x02, 0xfa, 0x4d, 0x34, 0xb7, 0x49, 0x41, 0xc8,
+	0x37, 0x50, 0x13, 0x93, 0x34, 0x4a, 0xa4, 0x57, 0x45, 0xf0, 0x97, 0x05, 0x70, 0x07, 0x15, 0x39,
+	0xde, 0x00, 0xfd, 0xbf, 0x5b, 0xb0, 0xb3, 0x74, 0x3e, 0xb2, 0x0d, 0xe5, 0x28, 0x44, 0x3f, 0x38,
+	0xb4, 0x1c, 0x85, 0x84, 0x40, 0x25, 0x09, 0xc6, 0xfa, 0x8c, 0x0e, 0x45, 0x99, 0xec, 0x83, 0x1b,
+	0x32, 0x31, 0x48, 0xa3, 0x89, 0xa2, 0xe0, 0x51, 0x1c, 0x5a, 0xfc, 0x44, 0x5e, 0x01, 0x8c, 0xa3,
+	0x98, 0x09, 0xc9, 0x13, 0x26, 0xbc, 0xca, 0xbe, 0x75, 0xe0, 0x3e, 0x7b, 0x5c, 0x30, 0xa8, 0x9b,
+	0x06, 0x83, 0x1b, 0x96, 0xbe, 0xcb, 0x30, 0xb4, 0x00, 0x27, 0x5f, 0x03, 0x09, 0x59, 0xcc, 0x24,
+	0x0b, 0x7b, 0x85, 0x4d, 0xaa, 0xfb, 0xd6, 0x81, 0x43, 0xef, 0x1b, 0xcd, 0xbb, 0x39, 0xfc, 0x08,
+	0xec, 0x8f, 0x3c, 0xbd, 0x19, 0xc6, 0xfc, 0xa3, 0x57, 0xc3, 0xa3, 0x3f, 0x28, 0xfc, 0xe9, 0x47,
+	0xa3, 0xa2, 0x39, 0x88, 0x1c, 0x40, 0x2d, 0x0e, 0xfa, 0x2c, 0x16, 0xde, 0x06, 0x1a, 0x56, 0x2f,
+	0xc0, 0xcf, 0x94, 0x82, 0x1a, 0x3d, 0xf9, 0x0a, 0xb6, 0x33, 0x4b, 0x0c, 0xc3, 0x46, 0x2b, 0xb6,
+	0xcc, 0xd7, 0x33, 0x0d, 0x7b, 0x0a, 0xd5, 0x94, 0x4d, 0xb8, 0xf0, 0x9c, 0x7d, 0x6b, 0x29, 0x4c,
+	0xc6, 0xbd, 0x94, 0x4d, 0x38, 0xd5, 0x20, 0xf2, 0x6b, 0xc8, 0xe8, 0x3d, 0xcd, 0x02, 0xdc, 0x73,
+	0xd3, 0x7c, 0x54, 0x50, 0xe1, 0xbf, 0x04, 0xb7, 0x40, 0x55, 0x51, 0x50, 0x58, 0x13, 0x17, 0x94,
+	0xc9, 0x2e, 0x54, 0x27, 0x81, 0xbc, 0x16, 0x5e, 0x19, 0xf9, 0x7a, 0xe1, 0x0f, 0xa0, 0x8a, 0x56,
+	0xdd, 0x29, 0x90, 0xbb, 0x50, 0x1d, 0xf0, 0x98, 0xa7, 0x26, 0x84, 0x7a, 0xb1, 0x1c, 0xde, 0xca,
+	0xad, 0xf0, 0xfa, 0xc7, 0x60, 0x67, 0x7e, 0x25, 0x2f, 0xc0, 0x16, 0x32, 0x90, 0x53, 0xc1, 0x84,
+	0x57, 0xda, 0xb7, 0x96, 0x32, 0x2f, 0x83, 0x75, 0x10, 0x42, 0x73, 0xa8, 0xff, 0x47, 0xd8, 0x5e,
+	0xd4, 0xe5, 0x06, 0x96, 0x0a, 0x06, 0xbe, 0x00, 0x7b, 0x10, 0x48, 0x36, 0xe2, 0xe9, 0x0c, 0x0d,
+	0xdf, 0x5e, 0x4c, 0x6b, 0x24, 0x36, 0x0d, 0x80, 0xe6, 0x50, 0xff, 0x6f, 0x16, 0xec, 0x2c, 0x95,
+	0xe0, 0x67, 0x4b, 0x6c, 0x77, 0x98, 0x32, 0xf6, 0x17, 0xd6, 0x0b, 0x03, 0xc9, 0x4c, 0x5d, 0xee,
+	0x35, 0x46, 0x9c, 0x8f, 0x62, 0xd6, 0xc8, 0x1a, 0x50, 0xa3, 0x9b, 0xf5, 0x1b, 0x0a, 0x1a, 0xde,
+	0x0a, 0x24, 0x23, 0xdf, 0xc3, 0xa6, 0xe9, 0x03, 0x9a, 0x5d, 0x5d, 0xcb, 0x76, 0x0d, 0x1e, 0xe9,
+	0x5f, 0x43, 0x6d, 0x10, 0x73, 0xc1, 0x42, 0x93, 0xe6, 0x0f, 0x0b, 0xae, 0x38, 0xe1, 0x3c, 0x6e,
+	0x5e, 0x07, 0xc9, 0x88, 0x51, 0x03, 0x5a, 0xaa, 0xc1, 0x8d, 0xcf, 0x51, 0x83, 0xf6, 0xa7, 0x6a,
+	0xf0, 0x09, 0xb8, 0x32, 0x18, 0xf5, 0x26, 0x81, 0x94, 0x2c, 0x4d, 0x3c, 0x07, 0x1d, 0x07, 0x32,
+	0x18, 0x5d, 0xea, 0x2f, 0xfe, 0xbf, 0x2d, 0xd8, 0x5e, 0xec, 0x42, 0xb7, 0x02, 0xe2, 0xcd, 0xdb,
+	0xb0, 0x8e, 0x49, 0xb6, 0x54, 0x69, 0xda, 0xe7, 0x41, 0x1a, 0x66, 0x69, 0x8a, 0x8b, 0x3c, 0x80,
+	0x95, 0x42, 0x00, 0x09, 0x54, 0x46, 0x3c, 0x88, 0xd1, 0xb3, 0x0e, 0x45, 0x99, 0x7c, 0x07, 0x20,
+	0x64, 0x90, 0x4a, 0xed, 0xf3, 0xda, 0x5a, 0x9f, 0x3b, 0x88, 0x46, 0x8f, 0xbf, 0x00, 0x9b, 0x25,
+	0xa1, 0x26, 0x6e, 0xac, 0x25, 0x6e, 0xb0, 0x24, 0x44, 0xda, 0x1f, 0x60, 0x6b, 0xc0, 0xc7, 0x13,
+	0xe5, 0x24, 0xcd, 0xb5, 0xd7, 0x72, 0x37, 0x33, 0x02, 0x6e, 0xf0, 0x14, 0xaa, 0xaa, 0x50, 0x18,
+	0x3a, 0x72, 0x7b, 0xa1, 0xa1, 0x68, 0x27, 0xaa, 0xcc, 0x67, 0x54, 0x83, 0x48, 0x03, 0x6a, 0x78,
+	0x59, 0xe8, 0x4e, 0xe2, 0xae, 0x80, 0xe3, 0xd5, 0x42, 0x0d, 0xaa, 0xd8, 0xd5, 0x0c, 0xcf, 0x5d,
+	0xe8, 0x6a, 0x88, 0x16, 0xfe, 0x15, 0xb8, 0x05, 0xb6, 0x0a, 0x02, 0xa2, 0x4d, 0xc4, 0xf4, 0x82,
+	0xfc, 0x16, 0xaa, 0x41, 0x18, 0xb2, 0xd0, 0x2b, 0xaf, 0x3d, 0xa2, 0x06, 0xfa, 0xff, 0x74, 0x60,
+	0x6b, 0xe1, 0xaa, 0x2b, 0x06, 0xbe, 0xb4, 0x18, 0x78, 0x9d, 0x22, 0xe5, 0x3c, 0x45, 0xf6, 0xc0,
+	0xc6, 0xdf, 0xbe, 0x65, 0x33, 0x93, 0x0b, 0xf9, 0x9a, 0x3c, 0x06, 0x27, 0xe1, 0xb2, 0xc7, 0xfe,
+	0x1c, 0x09, 0x7d, 0x5f, 0xda, 0xd4, 0x4e, 0xb8, 0x6c, 0xab, 0xb5, 0xba, 0xac, 0x07, 0x29, 0x0b,
+	0x24, 0x0b, 0xef, 0x50, 0x74, 0x19, 0x54, 0xb1, 0xa6, 0x93, 0x10, 0x59, 0xeb, 0xd3, 0x26, 0x83,
+	0x2a, 0x47, 0xc9, 0x48, 0xc6, 0x3a, 0x63, 0x1c, 0xaa, 0x17, 0x2a, 0x33, 0xfb, 0x3c, 0x9c, 0x61,
+	0x2a, 0x38, 0x14, 0x65, 0x15, 0x66, 0xfe, 0x31, 0x61, 0x29, 0x86, 0x79, 0x31, 0x6e, 0xa6, 0x38,
+	0xaf, 0x04, 0x4b, 0xa9, 0x06, 0x91, 0xe7, 0xe0, 0x04, 0x42, 0x44, 0xa3, 0x84, 0xad, 0x8c, 0x74,
+	0x91, 0x31, 0x07, 0x92, 0xdf, 0x40, 0x56, 0xae, 0xbd, 0x39, 0x5b, 0xc7, 0xbb, 0x6e, 0x14, 0xc7,
+	0x39, 0x78, 0xb1, 0x65, 0x6c, 0x7e, 0x8e, 0x96, 0xb1, 0xf5, 0xa9, 0x96, 0xf1, 0x08, 0x6a, 0xfa,
+	0x32, 0xf0, 0xb6, 0xd1, 0x25, 0x66, 0x55, 0xe8, 0x72, 0x3b, 0x77, 0xe9, 0x72, 0x2f, 0xc1, 0xd1,
+	0x52, 0x2f, 0x90, 0x5e, 0x7d, 0x6d, 0x94, 0x6c, 0x0d, 0x3e, 0x96, 0xe4, 0xdb, 0x9c, 0xd8, 0x9f,
+	0x79, 0xf7, 0x7f, 0x32, 0x00, 0x86, 0x74, 0x32, 0x23, 0x47, 0xf9, 0xe8, 0x40, 0xd0, 0x39, 0x5f,
+	0xdc, 0x66, 0xac, 0x9b, 0x20, 0x1e, 0xac, 0x9a, 0x20, 0xea, 0x60, 0x4d, 0xd3, 0xd8, 0xdb, 0x45,
+	0x4f, 0x28, 0x91, 0x1c, 0x42, 0x35, 0x8e, 0x92, 0x1b, 0xe1, 0x3d, 0xc4, 0x1f, 0xed, 0x2e, 0x0f,
+	0x8a, 0x67, 0x51, 0x72, 0x43, 0x35, 0x84, 0x7c, 0x37, 0x9f, 0x28, 0x34, 0xe7, 0xd1, 0x4f, 0x70,
+	0xb2, 0x39, 0xe3, 0x0c, 0xa9, 0x7b, 0x60, 0x4f, 0xd2, 0x88, 0xa7, 0x91, 0x9c, 0x79, 0x5f, 0xe8,
+	0x8a, 0xca, 0xd6, 0x2a, 0x65, 0xd5, 0x1c, 0xed, 0x79, 0x3a, 0x65, 0x95, 0x4c, 0x7e, 0x0f, 0x9b,
+	0x42, 0xf2, 0x74, 0xd6, 0x9b, 0xf0, 0x28, 0x91, 0xc2, 0xfb, 0x72, 0xbf, 0xb4, 0xe4, 0x86, 0x16,
+	0x9f, 0xf6, 0x63, 0x66, 0xa2, 0xe4, 0x22, 0xf8, 0x12, 0xb1, 0xe4, 0x19, 0xd8, 0x4c, 0xc8, 0x68,
+	0xac, 0x1a, 0xdb, 0xde, 0x2d, 0x87, 0x9f, 0x26, 0xf2, 0x77, 0xcf, 0x0d, 0x2d, 0xc7, 0x91, 0x57,
+	0xb0, 0x35, 0x98, 0x0a, 0xc9, 0xc7, 0xbd, 0x61, 0xc4, 0xe2, 0x50, 0x78, 0x8f, 0x6f, 0x25, 0x7e,
+	0x13, 0xf5, 0xaf, 0x95, 0x9a, 0x6e, 0x0e, 0xe6, 0x0b, 0xf5, 0xc3, 0x87, 0x99, 0x5f, 0x16, 0x37,
+	0xf9, 0x05, 0xc6, 0xe0, 0x81, 0x51, 0x16, 0x36, 0x10, 0xfe, 0x7f, 0x4a, 0xe0, 0x16, 0x3e, 0xa8,
+	0xc8, 0xdc, 0xb0, 0x99, 0x69, 0x4c, 0x4a, 0x24, 0x4f, 0xd4, 0x7d, 0x92, 0x46, 0xc9, 0xa8, 0xf7,
+	0x21, 0x88, 0x75, 0x73, 0xfa, 0xe1, 0x1e, 0x75, 0xf4, 0xb7, 0xf7, 0x41, 0xac, 0x00, 0xc9, 0x74,
+	0xdc, 0x67, 0x29, 0x02, 0x54, 0x9f, 0x2a, 0x29, 0x80, 0xfe, 0xa6, 0x00, 0x8f, 0xc1, 0xee, 0x73,
+	0x1e, 0xa3, 0x1a, 0x3b, 0xd5, 0x0f, 0xf7, 0xe8, 0x86, 0xfa, 0xa2, 0x94, 0x2f, 0xc1, 0x56, 0xaf,
+	0x15, 0x54, 0xae, 0xed, 0x55, 0x8a, 0xa8, 0xd0, 0xef, 0x83, 0xf8, 0x64, 0x03, 0xaa, 0x1f, 0x82,
+	0x78, 0xca, 0xfc, 0x0b, 0x70, 0xf2, 0x70, 0x7f, 0xa2, 0x6d, 0x3f, 0x35, 0xa1, 0xd5, 0x33, 0x95,
+	0xb7, 0x2a, 0x51, 0xba, 0xb3, 0x09, 0xd3, 0x41, 0xf7, 0x7d, 0xd8, 0x2c, 0x26, 0xf7, 0xaa, 0xd1,
+	0xc9, 0xff, 0x57, 0x09, 0xea, 0xcb, 0xed, 0xe1, 0xff, 0xb8, 0xe2, 0xe7, 0x55, 0x6f, 0xdd, 0xa5,
+	0xea, 0x57, 0xdd, 0xfd, 0x4b, 0xc3, 0x5b, 0xf5, 0xf6, 0xd8, 0xfa, 0xdf, 0x12, 0xec, 0xe2, 0xf9,
+	0x9a, 0x7c, 0x3c, 0x66, 0x2b, 0x47, 0x11, 0x0b, 0xed, 0x3c, 0x84, 0xca, 0x54, 0xb0, 0xd4, 0x5c,
+	0x6a, 0x9f, 0x6a, 0x0b, 0x88, 0xc9, 0x1b, 0xbb, 0x55, 0x68, 0xec, 0x85, 0xeb, 0xa6, 0xf2, 0xb3,
+	0xae, 0x9b, 0xea, 0x9d, 0xaf, 0x1b, 0xff, 0x0d, 0xb8, 0x05, 0xa3, 0xee, 0x3a, 0xf6, 0xb3, 0x71,
+	0x10, 0xc5, 0xd9, 0x3c, 0x85, 0x0b, 0xff, 0x1f, 0x25, 0x70, 0x0b, 0xaf, 0xca, 0x95, 0x6f, 0x8e,
+	0xa7, 0x50, 0x1b, 0xf0, 0xf1, 0x38, 0x92, 0xc6, 0x35, 0xbb, 0x8b, 0x2f, 0xd2, 0x26, 0xea, 0xa8,
+	0xc1, 0x90, 0xaf, 0xd4, 0x0e, 0x43, 0xe1, 0x59, 0x58, 0xb3, 0xf7, 0x17, 0xb1, 0x94, 0x0d, 0x29,
+	0xaa, 0xc9, 0xaf, 0x60, 0x73, 0xfe, 0x20, 0x1a, 0xea, 0xe7, 0x22, 0x46, 0xce, 0xbc, 0x87, 0x86,
+	0xc2, 0x1f, 0x82, 0x93, 0x6f, 0xaf, 0x0c, 0x13, 0xd7, 0xc1, 0x37, 0x99, 0x61, 0x4a, 0x56, 0x65,
+	0x9a, 0x06, 0x1f, 0xcd, 0x29, 0x95, 0xa8, 0xfa, 0x7b, 0x18, 0x0d, 0x87, 0x3d, 0x99, 0xb2, 0xec,
+	0xb5, 0xbd, 0xf4, 0x7e, 0x6e, 0x45, 0xc3, 0x61, 0x37, 0x65, 0x8c, 0xda, 0xa1, 0x91, 0xfc, 0xef,
+	0xc1, 0x2d, 0x28, 0x48, 0x03, 0x2a, 0xc3, 0x28, 0x66, 0xe6, 0x5d, 0xb3, 0xb7, 0x9a, 0xfe, 0x3a,
+	0x8a, 0x19, 0x45, 0x9c, 0x3f, 0x86, 0x9d, 0x25, 0x85, 0x32, 0xd6, 0x6c, 0x81, 0xc6, 0x2a, 0x59,
+	0xf9, 0x7f, 0x3e, 0x34, 0x59, 0x66, 0x30, 0x52, 0xc5, 0x61, 0x8e, 0x8c, 0xe6, 0x5a, 0x34, 0x5b,
+	0xaa, 0xab, 0xb2, 0x1f, 0x25, 0x41, 0x3a, 0x33, 0x73, 0x8d, 0x59, 0xf9, 0x0d, 0xa8, 0x69, 0x47,
+	0xe2, 0xf1, 0xd9, 0x30, 0xeb, 0x52, 0x29, 0x1b, 0xe6, 0x4e, 0x2a, 0xcf, 0x9d, 0xe4, 0xff, 0x12,
+	0x60, 0x5e, 0x4b, 0x8a, 0xa3, 0x7a, 0x4c, 0x09, 0xb7, 0x54, 0xa2, 0xbf, 0x0f, 0x9b, 0xc5, 0xee,
+	0x5d, 0x44, 0x94, 0x34, 0xe2, 0x09, 0xb8, 0x85, 0x3e, 0x5d, 0x04, 0x58, 0x08, 0x38, 0x3c, 0x81,
+	0xed, 0xc5, 0x57, 0x19, 0x71, 0x61, 0xe3, 0xea, 0xfc, 0xed, 0xf9, 0xc5, 0x8f, 0xe7, 0xf5, 0x7b,
+	0xc4, 0x86, 0x4a, 0xf7, 0xa2, 0x75, 0x51, 0x2f, 0x91, 0x1d, 0x70, 0x4f, 0xcf, 0x7b, 0x97, 0xf4,
+	0xe2, 0x0d, 0x6d, 0x77, 0x3a, 0xf5, 0xb2, 0x52, 0xb5, 0x2e, 0xce, 0xdb, 0x75, 0xeb, 0xf0, 0x24,
+	0x1b, 0x3c, 0x3b, 0x38, 0xde, 0xee, 0x80, 0x7b, 0x75, 0xde, 0xb9, 0x6c, 0x37, 0x4f, 0x5f, 0x9f,
+	0xb6, 0x5b, 0xf5, 0x7b, 0x04, 0xa0, 0xf6, 0xfa, 0xaa, 0x7b, 0x45, 0xdb, 0xf5, 0x92, 0x92, 0x8f,
+	0x9b, 0xdd, 0xd3, 0xf7, 0xed, 0x7a, 0x59, 0xc9, 0xcd, 0xb3, 0x8b, 0x4e, 0xbb, 0x55, 0xb7, 0x0e,
+	0x5b, 0xb0, 0xb5, 0xd0, 0xc9, 0x94, 0x19, 0xb4, 0x7d, 0x76, 0xdc, 0x6d, 0x77, 0xf4, 0x0e, 0x27,
+	0x67, 0x17, 0xcd, 0xb7, 0x9d, 0x7a, 0x89, 0x6c, 0x03, 0xb4, 0xae, 0x2e, 0xcf, 0x4e, 0x9b, 0xa8,
+	0xc3, 0x5d, 0x2e, 0x8f, 0x69, 0xfb, 0xbc, 0x5b, 0xb7, 0xfa, 0x35, 0x2c, 0xbc, 0x6f, 0xff, 0x37,
+	0x00, 0x63, 0x1c, 0xaa, 0x2b, 0x86, 0x12, 0x00, 0x00,
 }
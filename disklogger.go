@@ -6,13 +6,16 @@
 package devdashboard
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/urld/devdashboard/devdashpb"
@@ -20,11 +23,38 @@ import (
 )
 
 // DiskMutationLogger logs mutations to disk.
+//
+// As MutationSource, it only yields the mutations logged since the previous
+// call to GetMutations, so a corpus can be updated incrementally.
 type DiskMutationLogger struct {
 	directory string
 
-	mu   sync.Mutex
-	done bool // true after first GetMutations
+	// Rotation is the policy for starting new log files. It must not be
+	// changed after the first call to Log.
+	Rotation Rotation
+
+	mu        sync.Mutex
+	offsets   map[string]int64 // segment path -> uncompressed offset of the first unsent record
+	sent      map[string]bool  // compressed segment paths sent completely
+	changed   chan struct{}    // closed and replaced by Log
+	recovered map[string]bool  // file paths checked for torn records by Log
+	lease     *os.File         // locked LockFile, see Acquire
+
+	current       string // path of the log file Log appends to
+	period        string // name of the rotation period current belongs to
+	records       int    // number of records in current
+	sealed        string // path of the previous log file, until the manifest is written
+	sealedRecords int    // number of records in sealed
+}
+
+// compressedSuffix is the suffix of compressed log files. The log file
+// "devdashboard-2019-01-02.mutlog" is compressed to
+// "devdashboard-2019-01-02.mutlog.gz", see Compress.
+const compressedSuffix = ".gz"
+
+// segmentPath returns the path of the uncompressed log file of path.
+func segmentPath(path string) string {
+	return strings.TrimSuffix(path, compressedSuffix)
 }
 
 // NewDiskMutationLogger creates a new DiskMutationLogger, which will create
@@ -36,16 +66,19 @@ func NewDiskMutationLogger(directory string) *DiskMutationLogger {
 	return &DiskMutationLogger{directory: directory}
 }
 
-// filename returns the filename to write to. The oldest filename must come
-// first in lexical order.
-func (d *DiskMutationLogger) filename() string {
-	now := time.Now().UTC()
-	name := fmt.Sprintf("devdashboard-%s.mutlog", now.Format("2006-01-02"))
+// path returns the full path of the named log file.
+func (d *DiskMutationLogger) path(name string) string {
 	return filepath.Join(d.directory, name)
 }
 
-// Log will write m to disk. If a mutation file does not exist for the current
-// day, it will be created.
+// Log will write m to disk. A new log file is started as configured by
+// d.Rotation, by default for each day.
+//
+// Before appending to a file for the first time, a torn record left at its
+// end by a crash is removed with a warning.
+//
+// Only one process may log to a directory at a time. Log returns a
+// *LockedError if another process holds the lease, see Acquire.
 func (d *DiskMutationLogger) Log(m *devdashpb.Mutation) error {
 	data, err := proto.Marshal(m)
 	if err != nil {
@@ -53,33 +86,104 @@ func (d *DiskMutationLogger) Log(m *devdashpb.Mutation) error {
 	}
 	d.mu.Lock()
 	defer d.mu.Unlock()
-	return reclog.AppendRecordToFile(d.filename(), data)
+	if err := d.acquire(); err != nil {
+		return err
+	}
+	filename := d.filename()
+	if !d.recovered[filename] {
+		n, err := reclog.RecoverFile(filename)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			log.Printf("warning: removed torn record of %d bytes from the end of %s", n, filename)
+		}
+		if d.recovered == nil {
+			d.recovered = make(map[string]bool)
+		}
+		d.recovered[filename] = true
+	}
+	if err := reclog.AppendRecordToFile(filename, data); err != nil {
+		return err
+	}
+	d.records++
+	if d.records == 1 {
+		if err := d.writeManifest(); err != nil {
+			log.Printf("warning: unable to write manifest: %v", err)
+		}
+		d.sealed = ""
+	}
+	if d.changed != nil {
+		close(d.changed)
+		d.changed = nil
+	}
+	return nil
+}
+
+// waitChange returns a channel which is closed by the next call to Log.
+func (d *DiskMutationLogger) waitChange() <-chan struct{} {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.changed == nil {
+		d.changed = make(chan struct{})
+	}
+	return d.changed
 }
 
+// ForeachFile calls fn for each log file in lexical order, which is the
+// order the files were written. A compressed log file is skipped as long as
+// its uncompressed file exists.
 func (d *DiskMutationLogger) ForeachFile(fn func(fullPath string, fi os.FileInfo) error) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
+	return d.foreachFile(fn)
+}
+
+// foreachFile is like ForeachFile, but d.mu must be held.
+func (d *DiskMutationLogger) foreachFile(fn func(fullPath string, fi os.FileInfo) error) error {
 	if d.directory == "" {
 		panic("empty directory")
 	}
+	var prev string
 	// Walk guarantees that files are walked in lexical order, which we depend on.
 	return filepath.Walk(d.directory, func(path string, fi os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			// removed by Compress
+			return nil
+		}
 		if err != nil {
 			return err
 		}
 		if fi.IsDir() && path != filepath.Clean(d.directory) {
 			return filepath.SkipDir
 		}
-		if !strings.HasPrefix(fi.Name(), "devdashboard-") {
+		if !isLogFile(fi.Name()) {
 			return nil
 		}
-		if !strings.HasSuffix(fi.Name(), ".mutlog") {
+		if strings.HasSuffix(path, compressedSuffix) && segmentPath(path) == prev {
 			return nil
 		}
+		prev = path
 		return fn(path, fi)
 	})
 }
 
+// isLogFile reports whether name is the name of a log file, which may be
+// compressed.
+func isLogFile(name string) bool {
+	return strings.HasPrefix(name, "devdashboard-") &&
+		(strings.HasSuffix(name, ".mutlog") || strings.HasSuffix(name, ".mutlog"+compressedSuffix))
+}
+
+// resetFile makes the next GetMutations read the log file from the
+// beginning.
+func (d *DiskMutationLogger) resetFile(path string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.offsets, segmentPath(path))
+	delete(d.sent, segmentPath(path))
+}
+
 func (d *DiskMutationLogger) GetMutations(ctx context.Context) <-chan MutationStreamEvent {
 	ch := make(chan MutationStreamEvent, 50)
 	go func() {
@@ -97,18 +201,67 @@ func (d *DiskMutationLogger) GetMutations(ctx context.Context) <-chan MutationSt
 }
 
 func (d *DiskMutationLogger) sendMutations(ctx context.Context, ch chan<- MutationStreamEvent) error {
+	if err := d.sendSnapshot(ctx, ch); err != nil {
+		return err
+	}
 	return d.ForeachFile(func(fullPath string, fi os.FileInfo) error {
-		return reclog.ForeachFileRecord(fullPath, func(off int64, hdr, rec []byte) error {
+		// d.mu is held by ForeachFile.
+		seg := segmentPath(fullPath)
+		compressed := seg != fullPath
+		start := d.offsets[seg]
+		if d.sent[seg] || !compressed && start >= fi.Size() {
+			return nil
+		}
+		f, err := os.Open(fullPath)
+		if os.IsNotExist(err) {
+			// compressed meanwhile, read by the next call
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		var r io.Reader = f
+		if compressed {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				return fmt.Errorf("error in %s: %v", fullPath, err)
+			}
+			if _, err := io.CopyN(ioutil.Discard, zr, start); err != nil {
+				return fmt.Errorf("error in %s: %v", fullPath, err)
+			}
+			r = zr
+		} else if _, err := f.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		err = reclog.ForeachRecord(r, start, func(off int64, hdr, rec []byte) error {
 			m := new(devdashpb.Mutation)
 			if err := proto.Unmarshal(rec, m); err != nil {
 				return err
 			}
 			select {
 			case ch <- MutationStreamEvent{Mutation: m}:
+				d.offsets[seg] = off + int64(len(hdr)+len(rec))
 				return nil
 			case <-ctx.Done():
 				return ctx.Err()
 			}
 		})
+		if _, ok := err.(*reclog.TruncatedError); ok {
+			// The record is still being written, or will be removed by the
+			// next Log. Either way, it is retried by the next call. Torn
+			// records of compressed files are never completed.
+			err = nil
+		}
+		if err != nil {
+			return fmt.Errorf("error in %s: %v", fullPath, err)
+		}
+		if compressed {
+			if d.sent == nil {
+				d.sent = make(map[string]bool)
+			}
+			d.sent[seg] = true
+		}
+		return nil
 	})
 }
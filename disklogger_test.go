@@ -0,0 +1,77 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestDiskMutationLoggerTornRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	d := NewDiskMutationLogger(dir)
+	checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "ABC"}}))
+	f, err := os.OpenFile(d.filename(), os.O_WRONLY|os.O_APPEND, 0)
+	checkErr(t, err)
+	_, err = f.WriteString("REC2@1c+1")
+	checkErr(t, err)
+	checkErr(t, f.Close())
+
+	// readers skip the torn record:
+	c := new(Corpus)
+	checkErr(t, c.Initialize(context.Background(), NewDiskMutationLogger(dir)))
+	if len(c.Projects) != 1 {
+		t.Fatalf("expected project ABC, got %v", c.Projects)
+	}
+
+	// a new logger removes it before appending:
+	checkErr(t, d.Release())
+	d = NewDiskMutationLogger(dir)
+	checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "DEF"}}))
+	checkErr(t, c.Update(context.Background()))
+	if len(c.Projects) != 2 {
+		t.Errorf("expected projects ABC and DEF, got %v", c.Projects)
+	}
+}
+
+func TestDiskMutationLoggerLease(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	d1 := NewDiskMutationLogger(dir)
+	d2 := NewDiskMutationLogger(dir)
+	checkErr(t, d1.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "ABC"}}))
+	err = d2.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "DEF"}})
+	if _, ok := err.(*LockedError); !ok {
+		t.Fatalf("expected LockedError, got %v", err)
+	}
+	if err := d2.Acquire(); err == nil {
+		t.Fatalf("the lease should be held by the first logger")
+	}
+
+	// readers need no lease:
+	c := new(Corpus)
+	checkErr(t, c.Initialize(context.Background(), d2))
+	if len(c.Projects) != 1 {
+		t.Fatalf("expected project ABC, got %v", c.Projects)
+	}
+
+	checkErr(t, d1.Release())
+	checkErr(t, d2.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "DEF"}}))
+	checkErr(t, c.Update(context.Background()))
+	if len(c.Projects) != 2 {
+		t.Errorf("expected projects ABC and DEF, got %v", c.Projects)
+	}
+	if _, ok := d1.Log(&devdashpb.Mutation{}).(*LockedError); !ok {
+		t.Errorf("released lease should be lost to the other logger")
+	}
+}
@@ -4,7 +4,18 @@
 
 package devdashboard
 
-import "github.com/urld/devdashboard/devdashpb"
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
 
 type GitRepo struct {
 	c *Corpus
@@ -12,6 +23,66 @@ type GitRepo struct {
 	URL     string
 	commits map[string]*GitCommit
 	refs    []GitRef
+
+	// merged caches the ancestors of mergedHead, the default head. Readers
+	// fill it lazily, so it is guarded by mergedMu.
+	mergedMu   sync.Mutex
+	merged     map[string]struct{}
+	mergedHead string
+}
+
+// Commit returns the commit with the given sha1, or nil if the commit
+// is not known.
+func (r *GitRepo) Commit(sha1 string) *GitCommit {
+	return r.commits[sha1]
+}
+
+// ForeachCommit calls fn for each commit of the repository in an
+// unspecified order. If fn returns an error, iteration ends and the error
+// is returned.
+func (r *GitRepo) ForeachCommit(fn func(*GitCommit) error) error {
+	for _, gc := range r.commits {
+		if err := fn(gc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultHead returns the sha1 of the default branch. This is the commit
+// HEAD points to, or the head of the master branch if HEAD is unknown.
+func (r *GitRepo) DefaultHead() string {
+	var master string
+	for _, ref := range r.refs {
+		switch ref.Ref {
+		case "HEAD":
+			return ref.Sha1
+		case "refs/heads/master":
+			master = ref.Sha1
+		}
+	}
+	return master
+}
+
+// mergedCommits returns the commits reachable from the default head. The
+// returned set must not be modified.
+func (r *GitRepo) mergedCommits() map[string]struct{} {
+	r.mergedMu.Lock()
+	defer r.mergedMu.Unlock()
+	head := r.DefaultHead()
+	if r.merged == nil || r.mergedHead != head {
+		r.merged = r.ancestors(head)
+		r.mergedHead = head
+	}
+	return r.merged
+}
+
+// resetMerged drops the cached commits of the default branch, after refs
+// or the history changed.
+func (r *GitRepo) resetMerged() {
+	r.mergedMu.Lock()
+	r.merged = nil
+	r.mergedMu.Unlock()
 }
 
 type GitRef struct {
@@ -21,12 +92,99 @@ type GitRef struct {
 	Sha1 string
 }
 
+// Name returns the short name of the ref, such as "master" for
+// "refs/heads/master" or "v1.0" for "refs/tags/v1.0".
+func (ref GitRef) Name() string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/remotes/"} {
+		if strings.HasPrefix(ref.Ref, prefix) {
+			return ref.Ref[len(prefix):]
+		}
+	}
+	return ref.Ref
+}
+
+// IsTag reports whether the ref is a tag.
+func (ref GitRef) IsTag() bool {
+	return strings.HasPrefix(ref.Ref, "refs/tags/")
+}
+
+// IsBranch reports whether the ref is a local branch.
+func (ref GitRef) IsBranch() bool {
+	return strings.HasPrefix(ref.Ref, "refs/heads/")
+}
+
+// Commit returns the commit the ref points to, or nil if the commit is not
+// known.
+func (ref GitRef) Commit() *GitCommit {
+	return ref.r.commits[ref.Sha1]
+}
+
+// Refs returns all refs of the repository sorted by name.
+func (r *GitRepo) Refs() []GitRef {
+	ret := append([]GitRef(nil), r.refs...)
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Ref < ret[j].Ref })
+	return ret
+}
+
 type GitCommit struct {
 	r *GitRepo
 
 	Sha1     string
 	Raw      string
 	DiffTree map[string]*GitDiffTreeFile
+
+	// Following fields are parsed from Raw:
+	Tree       string
+	Parents    []string // sha1 of the parent commits
+	Author     GitPerson
+	AuthorTime time.Time
+	Committer  GitPerson
+	CommitTime time.Time
+	Msg        string
+}
+
+// Repo returns the repository the commit belongs to.
+func (gc *GitCommit) Repo() *GitRepo {
+	return gc.r
+}
+
+// IsMerged reports whether the commit is reachable from the default branch
+// of its repository.
+func (gc *GitCommit) IsMerged() bool {
+	_, ok := gc.r.mergedCommits()[gc.Sha1]
+	return ok
+}
+
+// Summary returns the first line of the commit message.
+func (gc *GitCommit) Summary() string {
+	s := strings.TrimSpace(gc.Msg)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// GitPerson is a person in a git commit.
+type GitPerson struct {
+	Str string // "Foo Bar <foo@bar.com>"
+}
+
+// Name returns the name portion of p.Str.
+func (p GitPerson) Name() string {
+	if i := strings.Index(p.Str, " <"); i >= 0 {
+		return p.Str[:i]
+	}
+	return p.Str
+}
+
+// Email returns the email portion of p.Str.
+func (p GitPerson) Email() string {
+	i := strings.IndexByte(p.Str, '<')
+	j := strings.LastIndexByte(p.Str, '>')
+	if i < 0 || j < i {
+		return ""
+	}
+	return p.Str[i+1 : j]
 }
 
 type GitDiffTreeFile struct {
@@ -38,6 +196,326 @@ type GitDiffTreeFile struct {
 	binary  bool
 }
 
+// File returns the path of the changed file.
+func (f *GitDiffTreeFile) File() string { return f.file }
+
+// Added returns the number of added lines.
+func (f *GitDiffTreeFile) Added() int64 { return f.added }
+
+// Deleted returns the number of deleted lines.
+func (f *GitDiffTreeFile) Deleted() int64 { return f.deleted }
+
+// Binary reports whether the changed file is a binary file.
+func (f *GitDiffTreeFile) Binary() bool { return f.binary }
+
+func (c *Corpus) getOrCreateGitRepo(url string) *GitRepo {
+	r, ok := c.GitRepos[url]
+	if !ok {
+		// new repo
+		r = &GitRepo{
+			c:       c,
+			URL:     url,
+			commits: make(map[string]*GitCommit),
+		}
+		c.GitRepos[url] = r
+	}
+	return r
+}
+
 func (c *Corpus) processGitMutation(gm *devdashpb.GitMutation) {
-	// TODO
+	r := c.getOrCreateGitRepo(gm.Repo)
+	if gm.Commit != nil {
+		r.processGitCommit(gm.Commit)
+	}
+	for _, ref := range gm.Refs {
+		r.setRef(ref.Ref, ref.Sha1)
+	}
+	for _, name := range gm.DeletedRefs {
+		r.deleteRef(name)
+	}
+}
+
+func (r *GitRepo) processGitCommit(cm *devdashpb.GitCommit) {
+	gc, ok := r.commits[cm.Sha1]
+	if !ok {
+		// new commit
+		gc = &GitCommit{
+			r:    r,
+			Sha1: cm.Sha1,
+		}
+		r.commits[cm.Sha1] = gc
+	}
+	rawChanged := cm.Raw != "" && cm.Raw != gc.Raw
+	if !ok || rawChanged {
+		// the commit may complete the history of the default branch
+		r.resetMerged()
+	}
+	if rawChanged {
+		r.c.unlinkCommit(gc)
+		gc.Raw = cm.Raw
+		if err := gc.parseRaw(); err != nil {
+			log.Printf("could not parse commit %s: %v", gc.Sha1, err)
+		}
+	}
+	if cm.DiffTree != nil {
+		gc.DiffTree = make(map[string]*GitDiffTreeFile, len(cm.DiffTree.File))
+		for _, fm := range cm.DiffTree.File {
+			gc.DiffTree[fm.File] = &GitDiffTreeFile{
+				c:       gc,
+				file:    fm.File,
+				added:   fm.Added,
+				deleted: fm.Deleted,
+				binary:  fm.Binary,
+			}
+		}
+	}
+	// the diff tree decides which projects the commit belongs to, so link
+	// the commit after processing it
+	switch {
+	case rawChanged:
+		r.c.linkCommit(gc)
+	case cm.DiffTree != nil:
+		r.c.relinkCommit(gc)
+	}
+}
+
+func (r *GitRepo) setRef(name, sha1 string) {
+	r.resetMerged()
+	if strings.HasPrefix(name, "refs/tags/") {
+		r.c.updateTag(r, name, sha1)
+	}
+	for i := range r.refs {
+		if r.refs[i].Ref == name {
+			r.refs[i].Sha1 = sha1
+			return
+		}
+	}
+	r.refs = append(r.refs, GitRef{r: r, Ref: name, Sha1: sha1})
+}
+
+func (r *GitRepo) deleteRef(name string) {
+	r.resetMerged()
+	if strings.HasPrefix(name, "refs/tags/") {
+		r.c.updateTag(r, name, "")
+	}
+	for i := range r.refs {
+		if r.refs[i].Ref == name {
+			r.refs = append(r.refs[:i], r.refs[i+1:]...)
+			return
+		}
+	}
+}
+
+// GenMutationDiffs returns the mutations to turn the repository a into b.
+// a may be nil.
+func (a *GitRepo) GenMutationDiffs(b *GitRepo) []*devdashpb.GitMutation {
+	if a == nil {
+		a = &GitRepo{URL: b.URL}
+	}
+	var ret []*devdashpb.GitMutation
+	commits := make([]*GitCommit, 0, len(b.commits))
+	for _, gc := range b.commits {
+		commits = append(commits, gc)
+	}
+	sort.Slice(commits, func(i, j int) bool {
+		if !commits[i].CommitTime.Equal(commits[j].CommitTime) {
+			return commits[i].CommitTime.Before(commits[j].CommitTime)
+		}
+		return commits[i].Sha1 < commits[j].Sha1
+	})
+	for _, gc := range commits {
+		if cm := a.commits[gc.Sha1].GenMutationDiff(gc); cm != nil {
+			ret = append(ret, &devdashpb.GitMutation{Repo: b.URL, Commit: cm})
+		}
+	}
+
+	var refs devdashpb.GitMutation
+	for _, ref := range b.Refs() {
+		if sha1, ok := a.refSha1(ref.Ref); !ok || sha1 != ref.Sha1 {
+			refs.Refs = append(refs.Refs, &devdashpb.GitRef{Ref: ref.Ref, Sha1: ref.Sha1})
+		}
+	}
+	for _, ref := range a.Refs() {
+		if _, ok := b.refSha1(ref.Ref); !ok {
+			refs.DeletedRefs = append(refs.DeletedRefs, ref.Ref)
+		}
+	}
+	if len(refs.Refs) > 0 || len(refs.DeletedRefs) > 0 {
+		refs.Repo = b.URL
+		ret = append(ret, &refs)
+	}
+	return ret
+}
+
+func (r *GitRepo) refSha1(name string) (string, bool) {
+	for _, ref := range r.refs {
+		if ref.Ref == name {
+			return ref.Sha1, true
+		}
+	}
+	return "", false
+}
+
+func (a *GitCommit) GenMutationDiff(b *GitCommit) *devdashpb.GitCommit {
+	var ret *devdashpb.GitCommit // lazily initialized by diff
+	diff := func() *devdashpb.GitCommit {
+		if ret == nil {
+			ret = &devdashpb.GitCommit{Sha1: b.Sha1}
+		}
+		return ret
+	}
+	if a == nil {
+		a = &GitCommit{}
+	}
+	if a.Raw != b.Raw {
+		diff().Raw = b.Raw
+	}
+	if b.DiffTree != nil && !diffTreesEqual(a.DiffTree, b.DiffTree) {
+		dm := &devdashpb.GitDiffTree{}
+		for _, f := range b.DiffTree {
+			dm.File = append(dm.File, &devdashpb.GitDiffTreeFile{
+				File:    f.file,
+				Added:   f.added,
+				Deleted: f.deleted,
+				Binary:  f.binary,
+			})
+		}
+		sort.Slice(dm.File, func(i, j int) bool { return dm.File[i].File < dm.File[j].File })
+		diff().DiffTree = dm
+	}
+	return ret
+}
+
+func diffTreesEqual(a, b map[string]*GitDiffTreeFile) bool {
+	if a == nil || len(a) != len(b) {
+		return false
+	}
+	for file, fa := range a {
+		fb, ok := b[file]
+		if !ok || fa.added != fb.added || fa.deleted != fb.deleted || fa.binary != fb.binary {
+			return false
+		}
+	}
+	return true
+}
+
+// parseRaw parses the "git cat-file commit $sha1" output stored in Raw.
+func (gc *GitCommit) parseRaw() error {
+	hdr := gc.Raw
+	msg := ""
+	if i := strings.Index(hdr, "\n\n"); i >= 0 {
+		hdr, msg = hdr[:i], hdr[i+2:]
+	}
+	gc.Msg = msg
+	gc.Parents = nil
+	for _, line := range strings.Split(hdr, "\n") {
+		sp := strings.IndexByte(line, ' ')
+		if sp < 0 {
+			continue
+		}
+		key, val := line[:sp], line[sp+1:]
+		var err error
+		switch key {
+		case "tree":
+			gc.Tree = val
+		case "parent":
+			gc.Parents = append(gc.Parents, val)
+		case "author":
+			gc.Author, gc.AuthorTime, err = parsePersonTime(val)
+		case "committer":
+			gc.Committer, gc.CommitTime, err = parsePersonTime(val)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parsePersonTime parses a git person line like
+// "Foo Bar <foo@bar.com> 1546300800 +0100".
+func parsePersonTime(s string) (GitPerson, time.Time, error) {
+	gt := strings.LastIndexByte(s, '>')
+	if gt < 0 {
+		return GitPerson{}, time.Time{}, fmt.Errorf("malformed person line %q", s)
+	}
+	p := GitPerson{Str: s[:gt+1]}
+	f := strings.Fields(s[gt+1:])
+	if len(f) != 2 {
+		return p, time.Time{}, fmt.Errorf("malformed time in person line %q", s)
+	}
+	sec, err := strconv.ParseInt(f[0], 10, 64)
+	if err != nil {
+		return p, time.Time{}, fmt.Errorf("malformed time in person line %q", s)
+	}
+	tz, err := strconv.Atoi(f[1])
+	if err != nil {
+		return p, time.Time{}, fmt.Errorf("malformed zone in person line %q", s)
+	}
+	offset := (tz/100)*3600 + (tz%100)*60
+	return p, time.Unix(sec, 0).In(time.FixedZone(f[1], offset)), nil
+}
+
+var issueKeyRx = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+// linkCommit links the commit to all issues whose issue key is mentioned
+// in the commit message, unless the commit does not belong to the issue's
+// project. Commits mentioning issues which are not known yet are linked as
+// soon as the issue key shows up.
+func (c *Corpus) linkCommit(gc *GitCommit) {
+	for _, key := range issueKeyRx.FindAllString(gc.Msg, -1) {
+		if indexOfCommit(c.commitsByIssueKey[key], gc) < 0 {
+			c.commitsByIssueKey[key] = append(c.commitsByIssueKey[key], gc)
+		}
+		if i, ok := c.issuesByKey[key]; ok && i.acceptsCommit(gc) {
+			i.linkCommit(gc)
+		}
+	}
+}
+
+// unlinkCommit removes the links of the commit to the issues mentioned in
+// its commit message, before the message changes.
+func (c *Corpus) unlinkCommit(gc *GitCommit) {
+	for _, key := range issueKeyRx.FindAllString(gc.Msg, -1) {
+		commits := c.commitsByIssueKey[key]
+		if n := indexOfCommit(commits, gc); n >= 0 {
+			commits = append(commits[:n], commits[n+1:]...)
+		}
+		if len(commits) == 0 {
+			delete(c.commitsByIssueKey, key)
+		} else {
+			c.commitsByIssueKey[key] = commits
+		}
+		if i, ok := c.issuesByKey[key]; ok && i.Commits[gc.Sha1] == gc {
+			delete(i.Commits, gc.Sha1)
+		}
+	}
+}
+
+// indexOfCommit returns the index of the commit in commits, which may be
+// commits of different repos with the same hash, or -1.
+func indexOfCommit(commits []*GitCommit, gc *GitCommit) int {
+	for n, other := range commits {
+		if other.Sha1 == gc.Sha1 && other.r == gc.r {
+			return n
+		}
+	}
+	return -1
+}
+
+// CommitList returns the linked commits of the issue sorted by author time.
+func (i *Issue) CommitList() []*GitCommit {
+	ret := make([]*GitCommit, 0, len(i.Commits))
+	for _, gc := range i.Commits {
+		ret = append(ret, gc)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].AuthorTime.Before(ret[j].AuthorTime) })
+	return ret
+}
+
+func (i *Issue) linkCommit(gc *GitCommit) {
+	if i.Commits == nil {
+		i.Commits = make(map[string]*GitCommit)
+	}
+	i.Commits[gc.Sha1] = gc
 }
@@ -0,0 +1,398 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+const testCommitRaw = `tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904
+parent 1111111111111111111111111111111111111111
+author David Url <david@urld.io> 1546300800 +0100
+committer David Url <david@urld.io> 1546304400 +0100
+
+ABC-1: setup project
+
+configure ci build, see DEF-9
+`
+
+func TestGitCommitMutation(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: "https://example.com/abc.git",
+			Commit: &devdashpb.GitCommit{
+				Sha1: "2222222222222222222222222222222222222222",
+				Raw:  testCommitRaw,
+				DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+					{File: "README.md", Added: 10, Deleted: 2},
+				}},
+			},
+			Refs: []*devdashpb.GitRef{{Ref: "refs/heads/master", Sha1: "2222222222222222222222222222222222222222"}},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:       "i1",
+			Project:  "ABC",
+			IssueKey: "ABC-1",
+		},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	r, ok := c.GitRepos["https://example.com/abc.git"]
+	if !ok {
+		t.Fatal("GitRepo should exist")
+	}
+	gc := r.Commit("2222222222222222222222222222222222222222")
+	if gc == nil {
+		t.Fatal("commit should exist")
+	}
+	if gc.Author.Name() != "David Url" || gc.Author.Email() != "david@urld.io" {
+		t.Errorf("unexpected author %q", gc.Author.Str)
+	}
+	if !gc.AuthorTime.Equal(time.Unix(1546300800, 0)) {
+		t.Errorf("unexpected author time %v", gc.AuthorTime)
+	}
+	if len(gc.Parents) != 1 || gc.Parents[0] != "1111111111111111111111111111111111111111" {
+		t.Errorf("unexpected parents %v", gc.Parents)
+	}
+	if gc.Summary() != "ABC-1: setup project" {
+		t.Errorf("unexpected summary %q", gc.Summary())
+	}
+	if f := gc.DiffTree["README.md"]; f == nil || f.Added() != 10 || f.Deleted() != 2 {
+		t.Errorf("unexpected diff tree %v", gc.DiffTree)
+	}
+	if len(r.refs) != 1 {
+		t.Errorf("GitRepo should have 1 ref, got %d", len(r.refs))
+	}
+	if !gc.IsMerged() {
+		t.Error("commit should be merged into master")
+	}
+
+	i1 := c.Issues["i1"]
+	if i1.Commits[gc.Sha1] != gc {
+		t.Error("commit should have been linked to issue i1, which was created later")
+	}
+}
+
+func TestGitCommitRelink(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	const repo = "https://example.com/abc.git"
+	for _, key := range []string{"ABC-1", "ABC-2"} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Issue: &devdashpb.IssueMutation{Id: key, IssueKey: key}}))
+	}
+	for _, gc := range []*devdashpb.GitCommit{
+		testCommit("aaaa1", "", "ABC-1: setup", 0),
+		testCommit("aaaa1", "", "ABC-2: setup, see ABC-2", 0),
+		testCommit("aaaa1", "", "ABC-2: setup, see ABC-2 again", 0),
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: gc}}))
+	}
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	if commits := c.commitsByIssueKey["ABC-1"]; len(commits) != 0 {
+		t.Errorf("commit should not be indexed for ABC-1 anymore, got %v", commits)
+	}
+	if commits := c.commitsByIssueKey["ABC-2"]; len(commits) != 1 {
+		t.Errorf("commit should be indexed once for ABC-2, got %v", commits)
+	}
+	if commits := c.Issues["ABC-1"].Commits; len(commits) != 0 {
+		t.Errorf("commit should be unlinked from ABC-1, got %v", commits)
+	}
+	if gc := c.Issues["ABC-2"].Commits["aaaa1"]; gc == nil {
+		t.Error("commit should be linked to ABC-2")
+	}
+}
+
+func TestGitCommitIsMerged(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	const repo = "https://example.com/abc.git"
+	for _, gc := range []*devdashpb.GitCommit{
+		testCommit("aaaa1", "", "initial commit", 0),
+		testCommit("ffff1", "aaaa1", "feature", 60),
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: gc}}))
+	}
+	checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{
+		Repo: repo,
+		Refs: []*devdashpb.GitRef{{Ref: "refs/heads/master", Sha1: "aaaa1"}},
+	}}))
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	r := c.GitRepo("abc")
+	feature := r.Commit("ffff1")
+	if feature.IsMerged() {
+		t.Error("feature should not be merged yet")
+	}
+	// the cached commits of master must follow refs and new commits:
+	r.setRef("refs/heads/master", "mmmm2")
+	if feature.IsMerged() {
+		t.Error("feature should not be merged by an unknown commit")
+	}
+	r.processGitCommit(testCommit("mmmm2", "aaaa1\nparent ffff1", "merge feature", 120))
+	if !feature.IsMerged() {
+		t.Error("feature should be merged")
+	}
+	r.deleteRef("refs/heads/master")
+	if feature.IsMerged() {
+		t.Error("feature should not be merged without master")
+	}
+}
+
+// testCommit returns a commit mutation authored t seconds after 2019-01-01.
+func testCommit(sha1, parent, msg string, t int64) *devdashpb.GitCommit {
+	raw := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"
+	if parent != "" {
+		raw += "parent " + parent + "\n"
+	}
+	raw += fmt.Sprintf("author David Url <david@urld.io> %d +0000\n", 1546300800+t)
+	raw += fmt.Sprintf("committer David Url <david@urld.io> %d +0000\n", 1546300800+t)
+	raw += "\n" + msg + "\n"
+	return &devdashpb.GitCommit{
+		Sha1: sha1,
+		Raw:  raw,
+		DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+			{File: sha1 + ".txt", Added: 1},
+		}},
+	}
+}
+
+func TestGitChangelog(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	const repo = "https://example.com/abc.git"
+	for _, gc := range []*devdashpb.GitCommit{
+		testCommit("aaaa1", "", "initial commit", 0),
+		testCommit("bbbb2", "aaaa1", "ABC-1: first feature", 60),
+		testCommit("cccc3", "bbbb2", "ABC-2: second feature, see ABC-1", 120),
+		testCommit("dddd4", "bbbb2", "ABC-3: unmerged feature", 180),
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: gc}}))
+	}
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: repo,
+			Refs: []*devdashpb.GitRef{
+				{Ref: "refs/heads/master", Sha1: "cccc3"},
+				{Ref: "refs/heads/feature", Sha1: "dddd4"},
+				{Ref: "refs/tags/v1", Sha1: "aaaa1"},
+			},
+		},
+	}))
+	for _, key := range []string{"ABC-1", "ABC-2", "ABC-3"} {
+		checkErr(t, l.Log(&devdashpb.Mutation{
+			Issue: &devdashpb.IssueMutation{Id: key, Project: "ABC", IssueKey: key},
+		}))
+	}
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	r := c.GitRepo("abc")
+	if r == nil {
+		t.Fatal("GitRepo abc should exist")
+	}
+	cl, err := r.Changelog("v1", "master")
+	checkErr(t, err)
+	if len(cl.Commits) != 2 || cl.Commits[0].Sha1 != "cccc3" || cl.Commits[1].Sha1 != "bbbb2" {
+		t.Errorf("unexpected commits %v", cl.Commits)
+	}
+	if len(cl.Issues) != 2 || cl.Issues[0].IssueKey != "ABC-1" || cl.Issues[1].IssueKey != "ABC-2" {
+		t.Errorf("unexpected issues %v", cl.Issues)
+	}
+	if cl.Files != 2 || cl.Added != 2 || len(cl.Authors) != 1 {
+		t.Errorf("unexpected totals: %d files, %d added, %d authors", cl.Files, cl.Added, len(cl.Authors))
+	}
+
+	cl, err = r.Changelog("master", "feature")
+	checkErr(t, err)
+	if len(cl.Commits) != 1 || cl.Commits[0].Sha1 != "dddd4" {
+		t.Errorf("unexpected commits %v", cl.Commits)
+	}
+	if _, err := r.Changelog("v2", "master"); err == nil {
+		t.Error("unknown ref should fail")
+	}
+}
+
+func TestGitBranches(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	const repo = "https://example.com/abc.git"
+	for _, gc := range []*devdashpb.GitCommit{
+		testCommit("aaaa1", "", "initial commit", 0),
+		testCommit("bbbb2", "aaaa1", "ABC-1: first feature", 60),
+		testCommit("cccc3", "aaaa1", "ABC-2: second feature", 120),
+		testCommit("dddd4", "cccc3", "ABC-2: fix second feature", 180),
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: gc}}))
+	}
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: repo,
+			Refs: []*devdashpb.GitRef{
+				{Ref: "HEAD", Sha1: "bbbb2"},
+				{Ref: "refs/heads/master", Sha1: "bbbb2"},
+				{Ref: "refs/heads/feature/abc-2-second", Sha1: "dddd4"},
+				{Ref: "refs/heads/old", Sha1: "aaaa1"},
+				{Ref: "refs/tags/v1", Sha1: "aaaa1"},
+			},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{Id: "i2", Project: "ABC", IssueKey: "ABC-2"},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	branches := c.GitRepo("abc").Branches()
+	if len(branches) != 3 {
+		t.Fatalf("GitRepo should have 3 branches, got %d", len(branches))
+	}
+	feature, master, old := branches[0], branches[1], branches[2]
+	if feature.Name() != "feature/abc-2-second" || feature.Ahead != 2 || feature.Behind != 1 {
+		t.Errorf("unexpected feature branch %s: %d ahead, %d behind", feature.Name(), feature.Ahead, feature.Behind)
+	}
+	if len(feature.Issues) != 1 || feature.Issues[0].ID != "i2" {
+		t.Errorf("feature branch should be linked to issue i2, got %v", feature.Issues)
+	}
+	if !master.Default || master.Ahead != 0 || master.Behind != 0 {
+		t.Errorf("unexpected default branch %s", master.Name())
+	}
+	if !old.IsMerged() || old.Behind != 1 {
+		t.Errorf("branch old should be merged and 1 commit behind, got %d", old.Behind)
+	}
+	if !old.IsStale(24 * time.Hour) {
+		t.Error("branch old should be stale")
+	}
+}
+
+func TestGitBranchesMerged(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	const repo = "https://example.com/abc.git"
+	for _, gc := range []*devdashpb.GitCommit{
+		testCommit("aaaa1", "", "initial commit", 0),
+		testCommit("bbbb2", "aaaa1", "master", 60),
+		testCommit("ffff1", "aaaa1", "feature", 90),
+		testCommit("cccc3", "bbbb2", "master", 120),
+		testCommit("ffff2", "ffff1", "feature", 150),
+		testCommit("mmmm4", "cccc3\nparent ffff2", "merge feature", 180),
+		testCommit("dddd5", "mmmm4", "master", 240),
+		testCommit("ffff3", "ffff2", "feature after merge", 300),
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: gc}}))
+	}
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: repo,
+			Refs: []*devdashpb.GitRef{
+				{Ref: "refs/heads/master", Sha1: "dddd5"},
+				{Ref: "refs/heads/feature", Sha1: "ffff3"},
+				{Ref: "refs/heads/merged", Sha1: "ffff2"},
+				{Ref: "refs/heads/old", Sha1: "bbbb2"},
+			},
+		},
+	}))
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	want := map[string][2]int{"master": {0, 0}, "feature": {1, 4}, "merged": {0, 4}, "old": {0, 5}}
+	r := c.GitRepo("abc")
+	for _, b := range r.Branches() {
+		if got := [2]int{b.Ahead, b.Behind}; got != want[b.Name()] {
+			t.Errorf("branch %s: expected ahead/behind %v, got %v", b.Name(), want[b.Name()], got)
+		}
+	}
+	if n := r.BranchCount(); n != 4 {
+		t.Errorf("expected 4 branches, got %d", n)
+	}
+}
+
+func TestProjectRepos(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	const repo = "https://example.com/mono.git"
+	for _, gc := range []*devdashpb.GitCommit{
+		testCommit("aaaa1", "", "ABC-1, DEF-1: shared setup", 0),
+		testCommit("bbbb2", "aaaa1", "ABC-1: abc feature", 60),
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: gc}}))
+	}
+	checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: &devdashpb.GitCommit{
+		Sha1: "aaaa1",
+		DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+			{File: "abc/main.go", Added: 10},
+			{File: "def/main.go", Added: 20},
+		}},
+	}}}))
+	checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: repo, Commit: &devdashpb.GitCommit{
+		Sha1: "bbbb2",
+		DiffTree: &devdashpb.GitDiffTree{File: []*devdashpb.GitDiffTreeFile{
+			{File: "abc/feature.go", Added: 5},
+		}},
+	}}}))
+	for _, key := range []string{"ABC-1", "DEF-1"} {
+		checkErr(t, l.Log(&devdashpb.Mutation{
+			Issue: &devdashpb.IssueMutation{Id: key, Project: key[:3], IssueKey: key},
+		}))
+	}
+	// the projects declare their paths after the commits were linked:
+	checkErr(t, l.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{
+		Id:    "ABC",
+		Repos: []*devdashpb.ProjectRepo{{Repo: repo, Paths: []string{"abc"}}},
+	}}))
+	checkErr(t, l.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{
+		Id:    "DEF",
+		Repos: []*devdashpb.ProjectRepo{{Repo: repo, Paths: []string{"def/"}}},
+	}}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	abc, def := c.Projects["ABC"], c.Projects["DEF"]
+	if len(abc.Commits()) != 2 || len(def.Commits()) != 1 {
+		t.Errorf("unexpected project commits: ABC %d, DEF %d", len(abc.Commits()), len(def.Commits()))
+	}
+	if len(c.Issues["ABC-1"].Commits) != 2 || len(c.Issues["DEF-1"].Commits) != 1 {
+		t.Errorf("unexpected linked commits: ABC-1 %d, DEF-1 %d", len(c.Issues["ABC-1"].Commits), len(c.Issues["DEF-1"].Commits))
+	}
+	if !def.ContainsFile(c.GitRepo("mono"), "def/main.go") || def.ContainsFile(c.GitRepo("mono"), "abc/main.go") {
+		t.Error("project DEF should only contain files below def/")
+	}
+
+	// restricting DEF to other paths unlinks its commits:
+	c.processMutationLocked(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{
+		Id:    "DEF",
+		Repos: []*devdashpb.ProjectRepo{{Repo: repo, Paths: []string{"docs"}}},
+	}})
+	if len(c.Issues["DEF-1"].Commits) != 0 || len(def.Commits()) != 0 {
+		t.Errorf("unexpected commits of DEF: %d linked, %d total", len(c.Issues["DEF-1"].Commits), len(def.Commits()))
+	}
+
+	pm := (*Project)(nil).GenMutationDiff(abc)
+	if len(pm.Repos) != 1 || pm.Repos[0].Repo != repo || len(pm.Repos[0].Paths) != 1 {
+		t.Errorf("unexpected project repo diff %v", pm.Repos)
+	}
+}
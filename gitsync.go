@@ -0,0 +1,217 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// GitSyncer syncs a git repository. It mirrors the repository into a local
+// directory and logs new commits and changed branches and tags.
+type GitSyncer struct {
+	URL string // URL of the repository
+	Dir string // directory of the local mirror
+
+	commits map[string]bool   // sha1 of the logged commits
+	refs    map[string]string // logged refs -> sha1
+}
+
+// NewGitSyncer returns a syncer mirroring the repository at url into dir.
+func NewGitSyncer(url, dir string) *GitSyncer {
+	return &GitSyncer{URL: url, Dir: dir}
+}
+
+// Name returns the URL of the repository.
+func (s *GitSyncer) Name() string { return s.URL }
+
+// Sync fetches the repository and logs all commits and refs which are
+// neither known by the corpus nor logged by a previous call to Sync.
+func (s *GitSyncer) Sync(ctx context.Context, c *Corpus, l MutationLogger) error {
+	if err := s.fetch(ctx); err != nil {
+		return err
+	}
+	if s.commits == nil {
+		s.loadKnown(c)
+	}
+
+	refs, err := s.listRefs(ctx)
+	if err != nil {
+		return err
+	}
+	out, err := s.git(ctx, "rev-list", "--reverse", "--topo-order", "--branches", "--tags")
+	if err != nil {
+		return err
+	}
+	for _, sha1 := range strings.Fields(string(out)) {
+		if s.commits[sha1] {
+			continue
+		}
+		cm, err := s.commit(ctx, sha1)
+		if err != nil {
+			return err
+		}
+		if err := l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: s.URL, Commit: cm}}); err != nil {
+			return err
+		}
+		s.commits[sha1] = true
+	}
+
+	gm := &devdashpb.GitMutation{Repo: s.URL}
+	for ref, sha1 := range refs {
+		if s.refs[ref] != sha1 {
+			gm.Refs = append(gm.Refs, &devdashpb.GitRef{Ref: ref, Sha1: sha1})
+		}
+	}
+	for ref := range s.refs {
+		if _, ok := refs[ref]; !ok {
+			gm.DeletedRefs = append(gm.DeletedRefs, ref)
+		}
+	}
+	if len(gm.Refs) > 0 || len(gm.DeletedRefs) > 0 {
+		if err := l.Log(&devdashpb.Mutation{Git: gm}); err != nil {
+			return err
+		}
+	}
+	s.refs = refs
+	return nil
+}
+
+// loadKnown looks up the commits and refs already known by the corpus.
+func (s *GitSyncer) loadKnown(c *Corpus) {
+	s.commits = make(map[string]bool)
+	s.refs = make(map[string]string)
+	if c == nil {
+		return
+	}
+	c.RLock()
+	defer c.RUnlock()
+	r, ok := c.GitRepos[s.URL]
+	if !ok {
+		return
+	}
+	for sha1, gc := range r.commits {
+		if gc.Raw != "" {
+			s.commits[sha1] = true
+		}
+	}
+	for _, ref := range r.refs {
+		s.refs[ref.Ref] = ref.Sha1
+	}
+}
+
+// fetch clones the repository into s.Dir, or fetches it if it is cloned
+// already.
+func (s *GitSyncer) fetch(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(s.Dir, "HEAD")); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(s.Dir), 0700); err != nil {
+			return err
+		}
+		cmd := exec.CommandContext(ctx, "git", "clone", "--mirror", "--quiet", s.URL, s.Dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("git clone %s: %v\n%s", s.URL, err, out)
+		}
+		return nil
+	}
+	_, err := s.git(ctx, "fetch", "--prune", "--quiet", "origin")
+	return err
+}
+
+// listRefs returns HEAD and all branches and tags. Annotated tags are
+// resolved to the tagged commit.
+func (s *GitSyncer) listRefs(ctx context.Context) (map[string]string, error) {
+	out, err := s.git(ctx, "for-each-ref", "--format=%(refname) %(objectname) %(*objectname)", "refs/heads", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		f := strings.Fields(line)
+		if len(f) < 2 {
+			continue
+		}
+		refs[f[0]] = f[len(f)-1]
+	}
+	if out, err := s.git(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		refs["HEAD"] = strings.TrimSpace(string(out))
+	}
+	return refs, nil
+}
+
+// commit returns the raw commit and its diff tree to the first parent.
+func (s *GitSyncer) commit(ctx context.Context, sha1 string) (*devdashpb.GitCommit, error) {
+	raw, err := s.git(ctx, "cat-file", "commit", sha1)
+	if err != nil {
+		return nil, err
+	}
+	gc := &GitCommit{Sha1: sha1, Raw: string(raw)}
+	if err := gc.parseRaw(); err != nil {
+		return nil, fmt.Errorf("commit %s: %v", sha1, err)
+	}
+	args := []string{"diff-tree", "--numstat", "-r", "--no-commit-id"}
+	if len(gc.Parents) > 0 {
+		args = append(args, gc.Parents[0], sha1)
+	} else {
+		args = append(args, "--root", sha1)
+	}
+	out, err := s.git(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	dt, err := parseNumStat(out)
+	if err != nil {
+		return nil, fmt.Errorf("diff-tree of commit %s: %v", sha1, err)
+	}
+	return &devdashpb.GitCommit{Sha1: sha1, Raw: gc.Raw, DiffTree: dt}, nil
+}
+
+// parseNumStat parses the output of "git diff-tree --numstat".
+func parseNumStat(out []byte) (*devdashpb.GitDiffTree, error) {
+	dt := new(devdashpb.GitDiffTree)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		f := strings.SplitN(line, "\t", 3)
+		if len(f) != 3 {
+			return nil, fmt.Errorf("malformed numstat line %q", line)
+		}
+		file := &devdashpb.GitDiffTreeFile{File: f[2]}
+		if f[0] == "-" && f[1] == "-" {
+			file.Binary = true
+		} else {
+			var err error
+			if file.Added, err = strconv.ParseInt(f[0], 10, 64); err != nil {
+				return nil, fmt.Errorf("malformed numstat line %q", line)
+			}
+			if file.Deleted, err = strconv.ParseInt(f[1], 10, 64); err != nil {
+				return nil, fmt.Errorf("malformed numstat line %q", line)
+			}
+		}
+		dt.File = append(dt.File, file)
+	}
+	return dt, nil
+}
+
+// git runs a git command in the local mirror and returns its output.
+func (s *GitSyncer) git(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = s.Dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v\n%s", strings.Join(args, " "), err, stderr.Bytes())
+	}
+	return out, nil
+}
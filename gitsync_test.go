@@ -0,0 +1,96 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// recLogger records all logged mutations.
+type recLogger []*devdashpb.Mutation
+
+func (l *recLogger) Log(m *devdashpb.Mutation) error {
+	*l = append(*l, m)
+	return nil
+}
+
+func TestGitSyncer(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	git := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = src
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=David Url", "GIT_AUTHOR_EMAIL=david@urld.io",
+			"GIT_COMMITTER_NAME=David Url", "GIT_COMMITTER_EMAIL=david@urld.io")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(file, content, msg string) {
+		checkErr(t, ioutil.WriteFile(filepath.Join(src, file), []byte(content), 0600))
+		git("add", file)
+		git("commit", "-q", "-m", msg)
+	}
+	checkErr(t, os.MkdirAll(src, 0700))
+	git("init", "-q")
+	git("checkout", "-q", "-b", "master")
+	commit("README.md", "abc\n", "ABC-1: initial commit")
+	git("tag", "-a", "-m", "first release", "v1")
+
+	var l recLogger
+	s := NewGitSyncer(src, filepath.Join(dir, "mirror.git"))
+	checkErr(t, s.Sync(context.Background(), nil, &l))
+	if len(l) != 2 || l[0].Git.Commit == nil || len(l[1].Git.Refs) != 3 {
+		t.Fatalf("expected 1 commit and 3 refs, got %v", l)
+	}
+	if f := l[0].Git.Commit.DiffTree.File; len(f) != 1 || f[0].File != "README.md" || f[0].Added != 1 {
+		t.Errorf("unexpected diff tree %v", f)
+	}
+	first := l[0].Git.Commit.Sha1
+	for _, ref := range l[1].Git.Refs {
+		if ref.Sha1 != first {
+			t.Errorf("ref %s should point to %s, got %s", ref.Ref, first, ref.Sha1)
+		}
+	}
+
+	commit("README.md", "abc\ndef\n", "ABC-2: second commit")
+	git("tag", "-d", "v1")
+	l = nil
+	checkErr(t, s.Sync(context.Background(), nil, &l))
+	if len(l) != 2 || l[0].Git.Commit == nil {
+		t.Fatalf("expected 1 new commit and a refs update, got %v", l)
+	}
+	gm := l[1].Git
+	if len(gm.Refs) != 2 || len(gm.DeletedRefs) != 1 || gm.DeletedRefs[0] != "refs/tags/v1" {
+		t.Errorf("expected HEAD and master to move and v1 to be deleted, got %v", gm)
+	}
+
+	// a new syncer only logs what the corpus does not know yet:
+	c := &Corpus{}
+	ml := newLogger()
+	for _, m := range l {
+		checkErr(t, ml.Log(m))
+	}
+	ml.end()
+	checkErr(t, c.Initialize(context.Background(), ml))
+	l = nil
+	checkErr(t, NewGitSyncer(src, filepath.Join(dir, "mirror.git")).Sync(context.Background(), c, &l))
+	if len(l) != 1 || l[0].Git.Commit.Sha1 != first {
+		t.Errorf("expected the first commit only, got %v", l)
+	}
+}
@@ -49,3 +49,15 @@ func (s *set) has(id string) bool {
 	_, ok := s.m[id]
 	return ok
 }
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
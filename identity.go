@@ -0,0 +1,137 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Aliases maps issue tracker users to additional git identities.
+// Issue tracker users and git authors are matched by their email address;
+// aliases are needed if someone commits with other addresses than the one
+// known by the issue tracker.
+type Aliases struct {
+	emails map[string][]string // tracker user ID -> lower case emails
+}
+
+// ParseAliases parses an alias file. Each line maps an issue tracker user ID
+// to one or more email addresses used as git author. Empty lines and lines
+// starting with '#' are ignored:
+//
+//	# tracker user ID    git emails...
+//	urld                 david@urld.io david.url@example.com
+func ParseAliases(r io.Reader) (*Aliases, error) {
+	a := &Aliases{emails: make(map[string][]string)}
+	s := bufio.NewScanner(r)
+	lineNo := 0
+	for s.Scan() {
+		lineNo++
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) < 2 {
+			return nil, fmt.Errorf("line %d: expected user ID followed by one or more emails", lineNo)
+		}
+		for _, email := range f[1:] {
+			a.emails[f[0]] = append(a.emails[f[0]], strings.ToLower(email))
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// LoadAliases parses the named alias file.
+func LoadAliases(filename string) (*Aliases, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	a, err := ParseAliases(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return a, nil
+}
+
+// Emails returns all lower case email addresses of the tracker user u.
+// A nil *Aliases is valid and only returns the tracker email.
+func (a *Aliases) Emails(u *IssueTrackerUser) []string {
+	var emails []string
+	if u.Email != "" {
+		emails = append(emails, strings.ToLower(u.Email))
+	}
+	if a != nil {
+		emails = append(emails, a.emails[u.ID]...)
+	}
+	return emails
+}
+
+// IsUser reports whether the git person p is the tracker user u.
+func (a *Aliases) IsUser(u *IssueTrackerUser, p GitPerson) bool {
+	email := strings.ToLower(p.Email())
+	if email == "" {
+		return false
+	}
+	for _, e := range a.Emails(u) {
+		if e == email {
+			return true
+		}
+	}
+	return false
+}
+
+// UserActivity summarizes the issue tracker and git activity of a user.
+type UserActivity struct {
+	User *IssueTrackerUser
+
+	Assigned []*Issue // open issues assigned to the user
+	Closed   []*Issue // issues closed by the user
+	Commits  []*GitCommit
+
+	Added   int64 // lines added by the commits
+	Deleted int64 // lines deleted by the commits
+}
+
+// UserActivity collects the activity of the tracker user u. Git commits are
+// attributed to u if the author matches one of the users emails.
+//
+// If the corpus is updated concurrently, the caller must hold its read lock.
+func (c *Corpus) UserActivity(u *IssueTrackerUser, a *Aliases) *UserActivity {
+	ua := &UserActivity{User: u}
+	for _, i := range c.Issues {
+		if _, ok := i.Assignees[u.ID]; ok && !i.Closed {
+			ua.Assigned = append(ua.Assigned, i)
+		}
+		if i.Closed && i.ClosedBy != nil && i.ClosedBy.ID == u.ID {
+			ua.Closed = append(ua.Closed, i)
+		}
+	}
+	for _, r := range c.GitRepos {
+		for _, gc := range r.commits {
+			if !a.IsUser(u, gc.Author) {
+				continue
+			}
+			ua.Commits = append(ua.Commits, gc)
+			for _, f := range gc.DiffTree {
+				ua.Added += f.added
+				ua.Deleted += f.deleted
+			}
+		}
+	}
+	sort.Slice(ua.Assigned, func(i, j int) bool { return ua.Assigned[i].Updated.After(ua.Assigned[j].Updated) })
+	sort.Slice(ua.Closed, func(i, j int) bool { return ua.Closed[i].ClosedAt.After(ua.Closed[j].ClosedAt) })
+	sort.Slice(ua.Commits, func(i, j int) bool { return ua.Commits[i].AuthorTime.After(ua.Commits[j].AuthorTime) })
+	return ua
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestParseAliases(t *testing.T) {
+	a, err := ParseAliases(strings.NewReader(`
+# tracker user ID    git emails...
+urld                 david.url@example.com DAVID@other.example.com
+`))
+	checkErr(t, err)
+
+	u := &IssueTrackerUser{ID: "urld", Email: "david@urld.io"}
+	for _, p := range []GitPerson{
+		{Str: "David Url <david@urld.io>"},
+		{Str: "David Url <david.url@example.com>"},
+		{Str: "David <david@other.example.com>"},
+	} {
+		if !a.IsUser(u, p) {
+			t.Errorf("%q should be user %s", p.Str, u.ID)
+		}
+	}
+	if a.IsUser(u, GitPerson{Str: "Someone Else <else@example.com>"}) {
+		t.Error("Someone Else should not be user urld")
+	}
+
+	_, err = ParseAliases(strings.NewReader("urld\n"))
+	if err == nil {
+		t.Error("expected error for line without emails")
+	}
+}
+
+func TestUserActivity(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	urld := &devdashpb.TrackerUser{Id: "urld", Name: "David Url", Email: "david@urld.io"}
+	for _, im := range []*devdashpb.IssueMutation{
+		{Id: "i1", IssueKey: "ABC-1", Assignees: []*devdashpb.TrackerUser{urld}},
+		{Id: "i2", IssueKey: "ABC-2", Closed: pbBool(true), ClosedBy: urld},
+		{Id: "i3", IssueKey: "ABC-3", Assignees: []*devdashpb.TrackerUser{urld}, Closed: pbBool(true)},
+		{Id: "i4", IssueKey: "ABC-4", Assignees: []*devdashpb.TrackerUser{{Id: "else"}}},
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Issue: im}))
+	}
+	// commits by the tracker email, an alias and someone else:
+	author := func(gc *devdashpb.GitCommit, person string) *devdashpb.GitCommit {
+		gc.Raw = strings.Replace(gc.Raw, "author David Url <david@urld.io>", "author "+person, 1)
+		return gc
+	}
+	for _, gc := range []*devdashpb.GitCommit{
+		testCommit("aaaa1", "", "ABC-1: setup", 0),
+		author(testCommit("bbbb2", "aaaa1", "ABC-2: fix", 60), "David <DAVID.URL@example.com>"),
+		author(testCommit("cccc3", "bbbb2", "ABC-4: docs", 120), "Someone Else <else@example.com>"),
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Git: &devdashpb.GitMutation{Repo: "https://example.com/abc.git", Commit: gc}}))
+	}
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	a, err := ParseAliases(strings.NewReader("urld david.url@example.com\n"))
+	checkErr(t, err)
+	ua := c.UserActivity(c.Issues["i1"].Assignees["urld"], a)
+	if len(ua.Assigned) != 1 || ua.Assigned[0].ID != "i1" {
+		t.Errorf("expected open assigned issue i1, got %v", ua.Assigned)
+	}
+	if len(ua.Closed) != 1 || ua.Closed[0].ID != "i2" {
+		t.Errorf("expected closed issue i2, got %v", ua.Closed)
+	}
+	if len(ua.Commits) != 2 || ua.Commits[0].Sha1 != "bbbb2" || ua.Commits[1].Sha1 != "aaaa1" {
+		t.Errorf("expected commits bbbb2 and aaaa1, got %v", ua.Commits)
+	}
+	if ua.Added != 2 || ua.Deleted != 0 {
+		t.Errorf("expected 2 added lines, got +%d -%d", ua.Added, ua.Deleted)
+	}
+
+	// without aliases only the tracker email matches:
+	if ua := c.UserActivity(c.Issues["i1"].Assignees["urld"], nil); len(ua.Commits) != 1 {
+		t.Errorf("expected commit aaaa1 without aliases, got %v", ua.Commits)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// CustomField is a tracker specific field of an issue.
+type CustomField struct {
+	Key string
+
+	// Value is either a string, float64, bool or time.Time.
+	Value interface{}
+}
+
+func (f CustomField) String() string {
+	switch v := f.Value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(f.Value)
+}
+
+func customField(fm *devdashpb.CustomField) CustomField {
+	f := CustomField{Key: fm.Key}
+	switch v := fm.Value.(type) {
+	case *devdashpb.CustomField_StringVal:
+		f.Value = v.StringVal
+	case *devdashpb.CustomField_NumberVal:
+		f.Value = v.NumberVal
+	case *devdashpb.CustomField_BoolVal:
+		f.Value = v.BoolVal
+	case *devdashpb.CustomField_TimeVal:
+		f.Value = pbTime(v.TimeVal)
+	}
+	return f
+}
+
+func (f CustomField) pb() *devdashpb.CustomField {
+	fm := &devdashpb.CustomField{Key: f.Key}
+	switch v := f.Value.(type) {
+	case string:
+		fm.Value = &devdashpb.CustomField_StringVal{StringVal: v}
+	case float64:
+		fm.Value = &devdashpb.CustomField_NumberVal{NumberVal: v}
+	case bool:
+		fm.Value = &devdashpb.CustomField_BoolVal{BoolVal: v}
+	case time.Time:
+		fm.Value = &devdashpb.CustomField_TimeVal{TimeVal: pbTimestamp(v)}
+	}
+	return fm
+}
+
+func genCustomFieldDiffs(a, b map[string]CustomField) (fields []*devdashpb.CustomField, deletedFields []string) {
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			deletedFields = append(deletedFields, key)
+		}
+	}
+	for key, fb := range b {
+		if fa, ok := a[key]; ok && fa == fb {
+			continue
+		}
+		fields = append(fields, fb.pb())
+	}
+	return
+}
+
+// priorityRanks maps common priority names of issue trackers to a rank.
+// Lower ranks are more important.
+var priorityRanks = map[string]int{
+	"blocker":  0,
+	"critical": 1,
+	"highest":  1,
+	"urgent":   1,
+	"high":     2,
+	"major":    3,
+	"medium":   3,
+	"normal":   3,
+	"low":      4,
+	"minor":    4,
+	"lowest":   5,
+	"trivial":  5,
+}
+
+// PriorityRank returns a rank of the issue priority which allows comparing
+// priorities of different trackers. Lower ranks are more important.
+// Priorities like "P0" to "P5" rank by their number; issues without or with
+// an unknown priority rank last.
+func (i *Issue) PriorityRank() int {
+	const unknown = 100
+	p := strings.ToLower(strings.TrimSpace(i.Priority))
+	if rank, ok := priorityRanks[p]; ok {
+		return rank
+	}
+	if strings.HasPrefix(p, "p") {
+		if n, err := strconv.Atoi(p[1:]); err == nil && n >= 0 && n < unknown {
+			return n
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,195 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"sort"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// IssueLinkType is the kind of relation between two issues.
+type IssueLinkType int
+
+const (
+	LinkRelates    IssueLinkType = iota
+	LinkBlocks                   // From blocks To
+	LinkDuplicates               // From is a duplicate of To
+	LinkParent                   // To is the parent of From
+)
+
+func (t IssueLinkType) String() string {
+	switch t {
+	case LinkBlocks:
+		return "blocks"
+	case LinkDuplicates:
+		return "duplicates"
+	case LinkParent:
+		return "child of"
+	}
+	return "relates to"
+}
+
+// IssueLink is a directed relation between two issues. The linked issues
+// may not be known to the corpus yet.
+type IssueLink struct {
+	c *Corpus
+
+	Type IssueLinkType
+	From string // ID of the issue declaring the link
+	To   string // ID of the linked issue
+}
+
+// FromIssue returns the issue declaring the link.
+func (l IssueLink) FromIssue() *Issue { return l.c.Issues[l.From] }
+
+// ToIssue returns the linked issue, or nil if it is not known yet.
+func (l IssueLink) ToIssue() *Issue { return l.c.Issues[l.To] }
+
+// IncomingLinks returns the links other issues declare to the issue.
+func (i *Issue) IncomingLinks() []IssueLink {
+	return i.c.issueLinksTo[i.ID]
+}
+
+// BlockedBy returns all known issues which block the issue.
+func (i *Issue) BlockedBy() []*Issue {
+	var ret []*Issue
+	for _, l := range i.IncomingLinks() {
+		if l.Type == LinkBlocks {
+			if from := l.FromIssue(); from != nil {
+				ret = append(ret, from)
+			}
+		}
+	}
+	return sortedIssues(ret)
+}
+
+// OpenBlockers returns all open issues which block the issue.
+func (i *Issue) OpenBlockers() []*Issue {
+	var ret []*Issue
+	for _, b := range i.BlockedBy() {
+		if !b.Closed {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
+// Blocks returns all known issues blocked by the issue.
+func (i *Issue) Blocks() []*Issue {
+	return i.linkedIssues(LinkBlocks)
+}
+
+// Parent returns the parent issue, or nil if the issue has no known parent.
+func (i *Issue) Parent() *Issue {
+	if p := i.linkedIssues(LinkParent); len(p) > 0 {
+		return p[0]
+	}
+	return nil
+}
+
+// Subtasks returns all known issues which declare the issue as their parent.
+func (i *Issue) Subtasks() []*Issue {
+	var ret []*Issue
+	for _, l := range i.IncomingLinks() {
+		if l.Type == LinkParent {
+			if from := l.FromIssue(); from != nil {
+				ret = append(ret, from)
+			}
+		}
+	}
+	return sortedIssues(ret)
+}
+
+func (i *Issue) linkedIssues(t IssueLinkType) []*Issue {
+	var ret []*Issue
+	for _, l := range i.Links {
+		if l.Type == t {
+			if to := l.ToIssue(); to != nil {
+				ret = append(ret, to)
+			}
+		}
+	}
+	return sortedIssues(ret)
+}
+
+func sortedIssues(issues []*Issue) []*Issue {
+	sort.Slice(issues, func(i, j int) bool { return issues[i].IssueKey < issues[j].IssueKey })
+	return issues
+}
+
+func (c *Corpus) addIssueLink(i *Issue, lm *devdashpb.IssueLink) {
+	l := IssueLink{c: c, Type: IssueLinkType(lm.Type), From: i.ID, To: lm.Issue}
+	for _, existing := range i.Links {
+		if existing == l {
+			return
+		}
+	}
+	i.Links = append(i.Links, l)
+	c.issueLinksTo[l.To] = append(c.issueLinksTo[l.To], l)
+}
+
+func (c *Corpus) deleteIssueLink(i *Issue, lm *devdashpb.IssueLink) {
+	l := IssueLink{c: c, Type: IssueLinkType(lm.Type), From: i.ID, To: lm.Issue}
+	i.Links = removeIssueLink(i.Links, l)
+	c.issueLinksTo[l.To] = removeIssueLink(c.issueLinksTo[l.To], l)
+	if len(c.issueLinksTo[l.To]) == 0 {
+		delete(c.issueLinksTo, l.To)
+	}
+}
+
+func removeIssueLink(links []IssueLink, l IssueLink) []IssueLink {
+	for n, existing := range links {
+		if existing == l {
+			return append(links[:n:n], links[n+1:]...)
+		}
+	}
+	return links
+}
+
+func genIssueLinkDiffs(a, b []IssueLink) (links []*devdashpb.IssueLink, deletedLinks []*devdashpb.IssueLink) {
+	has := func(links []IssueLink, l IssueLink) bool {
+		for _, existing := range links {
+			if existing.Type == l.Type && existing.To == l.To {
+				return true
+			}
+		}
+		return false
+	}
+	for _, l := range a {
+		if !has(b, l) {
+			deletedLinks = append(deletedLinks, &devdashpb.IssueLink{Issue: l.To, Type: devdashpb.IssueLinkType(l.Type)})
+		}
+	}
+	for _, l := range b {
+		if !has(a, l) {
+			links = append(links, &devdashpb.IssueLink{Issue: l.To, Type: devdashpb.IssueLinkType(l.Type)})
+		}
+	}
+	return
+}
+
+// ExternalBlockers returns the open issues blocking i, which are not part of
+// the release.
+func (r *Release) ExternalBlockers(i *Issue) []*Issue {
+	var ret []*Issue
+	for _, b := range i.OpenBlockers() {
+		if !r.HasIssue(b) {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
+// HasIssue reports whether the issue is part of any milestone of the
+// release.
+func (r *Release) HasIssue(i *Issue) bool {
+	for id := range i.Milestones {
+		if _, ok := r.Milestones[id]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@
 package devdashboard
 
 import (
+	"sort"
 	"time"
 
 	"github.com/urld/devdashboard/devdashpb"
@@ -19,6 +20,11 @@ type Project struct {
 
 	Issues     map[string]*Issue
 	Milestones map[string]*Milestone
+
+	Workflow []WorkflowStatus // ordered statuses issues pass through
+	Labels   map[string]*Label
+
+	Repos map[string]*ProjectRepo // git repositories by URL, all if empty
 }
 
 type Release struct {
@@ -33,6 +39,14 @@ type Release struct {
 	Closed      bool
 
 	Milestones map[string]*Milestone
+
+	TagPattern string // glob pattern matching the git tags of the release
+
+	// tags matching the tag pattern, maintained by the corpus
+	tags struct {
+		pattern string
+		refs    []GitRef
+	}
 }
 
 func (r *Release) IsFrozen() bool {
@@ -45,6 +59,52 @@ func (r *Release) IsReleased() bool {
 	return t.After(r.ReleaseDate)
 }
 
+// ReleaseStatus is the state of a release in its lifecycle.
+type ReleaseStatus int
+
+const (
+	ReleasePlanned ReleaseStatus = iota
+	ReleaseFrozen
+	ReleaseReleased
+	ReleaseClosed
+)
+
+func (s ReleaseStatus) String() string {
+	switch s {
+	case ReleaseFrozen:
+		return "frozen"
+	case ReleaseReleased:
+		return "released"
+	case ReleaseClosed:
+		return "closed"
+	}
+	return "planned"
+}
+
+// Status returns the current status of the release.
+func (r *Release) Status() ReleaseStatus {
+	switch {
+	case r.Closed:
+		return ReleaseClosed
+	case r.Tag() != nil, !r.ReleaseDate.IsZero() && r.IsReleased():
+		return ReleaseReleased
+	case !r.FreezeDate.IsZero() && r.IsFrozen():
+		return ReleaseFrozen
+	}
+	return ReleasePlanned
+}
+
+// SortReleases sorts releases by release date.
+func SortReleases(releases []*Release) {
+	sort.Slice(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		if !a.ReleaseDate.Equal(b.ReleaseDate) {
+			return a.ReleaseDate.Before(b.ReleaseDate)
+		}
+		return a.Name < b.Name
+	})
+}
+
 type Milestone struct {
 	p *Project
 
@@ -62,6 +122,7 @@ func (m *Milestone) Project() *Project {
 }
 
 type Issue struct {
+	c *Corpus
 	p *Project
 
 	ID       string
@@ -78,18 +139,60 @@ type Issue struct {
 
 	Milestones map[string]*Milestone
 
-	Status string
-	Closed bool
+	Status        string
+	StatusHistory []IssueStatusChange
+	Closed        bool
 
 	ClosedAt time.Time
 	ClosedBy *IssueTrackerUser
 
 	Labels  map[string]struct{}
 	Commits map[string]*GitCommit
+	Links   []IssueLink // links declared by this issue
+
+	Priority     string // tracker specific, such as "P1" or "High"
+	Type         string // tracker specific, such as "bug", "story" or "task"
+	StoryPoints  float64
+	Estimate     time.Duration
+	CustomFields map[string]CustomField
 
 	URL string
 }
 
+func (i *Issue) Project() *Project {
+	return i.p
+}
+
+// IssueStatusChange records the time an issue entered a status. The time
+// is zero if the tracker did not report it.
+type IssueStatusChange struct {
+	Status string
+	Time   time.Time
+}
+
+// FirstCommit returns the linked commit with the earliest author time, or
+// nil if no commits are linked to the issue.
+func (i *Issue) FirstCommit() *GitCommit {
+	var first *GitCommit
+	for _, gc := range i.Commits {
+		if first == nil || gc.AuthorTime.Before(first.AuthorTime) {
+			first = gc
+		}
+	}
+	return first
+}
+
+// HasUnmergedCommits reports whether any of the linked commits is not merged
+// into the default branch of its repository yet.
+func (i *Issue) HasUnmergedCommits() bool {
+	for _, gc := range i.Commits {
+		if !gc.IsMerged() {
+			return true
+		}
+	}
+	return false
+}
+
 type IssueTrackerUser struct {
 	ID    string
 	Name  string
@@ -101,6 +204,7 @@ func (c *Corpus) getOrCreateProject(id string) *Project {
 	if !ok {
 		// new project
 		p = &Project{
+			c:          c,
 			ID:         id,
 			Issues:     make(map[string]*Issue),
 			Milestones: make(map[string]*Milestone),
@@ -121,6 +225,26 @@ func (c *Corpus) processProjectMutation(pm *devdashpb.ProjectMutation) {
 	for _, mm := range pm.Milestones {
 		c.processMilestoneMutation(mm)
 	}
+	if pm.Workflow != nil {
+		c.processWorkflowMutation(p, pm.Workflow)
+	}
+	for _, lm := range pm.Labels {
+		c.processLabelMutation(p, lm)
+	}
+	for _, id := range pm.DeletedLabels {
+		delete(p.Labels, id)
+	}
+	for _, rm := range pm.Repos {
+		c.processProjectRepoMutation(p, rm)
+	}
+	for _, url := range pm.DeletedRepos {
+		delete(p.Repos, url)
+	}
+	if len(pm.Repos) > 0 || len(pm.DeletedRepos) > 0 {
+		for _, i := range p.Issues {
+			i.relinkCommits()
+		}
+	}
 	for _, id := range pm.DeletedMilestones {
 		m, ok := p.Milestones[id]
 		if ok {
@@ -138,6 +262,7 @@ func (c *Corpus) processReleaseMutation(rm *devdashpb.ReleaseMutation) {
 	if !ok {
 		// new release
 		r = &Release{
+			c:  c,
 			ID: rm.Id,
 		}
 		c.Releases[rm.Id] = r
@@ -167,6 +292,10 @@ func (c *Corpus) processReleaseMutation(rm *devdashpb.ReleaseMutation) {
 	for _, id := range rm.DeletedMilestones {
 		delete(r.Milestones, id)
 	}
+	if rm.TagPattern != "" {
+		r.TagPattern = rm.TagPattern
+	}
+	c.indexTags(r)
 }
 
 func (c *Corpus) processIssueMutation(im *devdashpb.IssueMutation) {
@@ -174,18 +303,30 @@ func (c *Corpus) processIssueMutation(im *devdashpb.IssueMutation) {
 	if !ok {
 		// new issue
 		i = &Issue{
+			c:     c,
 			ID:    im.Id,
 			Owner: c.processTrackerUserMutation(im.Owner),
 		}
 		c.Issues[im.Id] = i
 	}
 	// update issue
-	if im.Project != "" {
+	relink := false
+	if im.Project != "" && (i.p == nil || i.p.ID != im.Project) {
+		if i.p != nil {
+			delete(i.p.Issues, i.ID)
+		}
 		i.p = c.getOrCreateProject(im.Project)
 		i.p.Issues[i.ID] = i
+		relink = true
 	}
-	if im.IssueKey != "" {
+	if im.IssueKey != "" && im.IssueKey != i.IssueKey {
+		delete(c.issuesByKey, i.IssueKey)
 		i.IssueKey = im.IssueKey
+		c.issuesByKey[i.IssueKey] = i
+		relink = true
+	}
+	if relink {
+		i.relinkCommits()
 	}
 	if im.Created != nil {
 		i.Created = pbTime(im.Created)
@@ -227,8 +368,21 @@ func (c *Corpus) processIssueMutation(im *devdashpb.IssueMutation) {
 		}
 		delete(i.Milestones, id)
 	}
-	if im.Status != "" {
+	if im.Status != "" && im.Status != i.Status {
 		i.Status = im.Status
+		// Changes without a time are recorded untimed rather than dated
+		// at creation, which would distort the durations derived from the
+		// history.
+		var t time.Time
+		switch {
+		case im.Updated != nil:
+			t = pbTime(im.Updated)
+		case im.ClosedAt != nil && im.Closed != nil && im.Closed.Val:
+			t = pbTime(im.ClosedAt)
+		case im.Created != nil:
+			t = i.Created
+		}
+		i.StatusHistory = append(i.StatusHistory, IssueStatusChange{Status: i.Status, Time: t})
 	}
 	if im.Closed != nil {
 		i.Closed = im.Closed.Val
@@ -251,6 +405,33 @@ func (c *Corpus) processIssueMutation(im *devdashpb.IssueMutation) {
 	if im.Url != "" {
 		i.URL = im.Url
 	}
+	if im.Priority != "" {
+		i.Priority = im.Priority
+	}
+	if im.Type != "" {
+		i.Type = im.Type
+	}
+	if im.StoryPoints != nil {
+		i.StoryPoints = im.StoryPoints.Val
+	}
+	if im.Estimate != nil {
+		i.Estimate = time.Duration(im.Estimate.Val) * time.Second
+	}
+	for _, fm := range im.CustomFields {
+		if i.CustomFields == nil {
+			i.CustomFields = make(map[string]CustomField)
+		}
+		i.CustomFields[fm.Key] = customField(fm)
+	}
+	for _, key := range im.DeletedCustomFields {
+		delete(i.CustomFields, key)
+	}
+	for _, lm := range im.Links {
+		c.addIssueLink(i, lm)
+	}
+	for _, lm := range im.DeletedLinks {
+		c.deleteIssueLink(i, lm)
+	}
 }
 
 func (c *Corpus) processTrackerUserMutation(um *devdashpb.TrackerUser) *IssueTrackerUser {
@@ -326,6 +507,15 @@ func (a *Project) GenMutationDiff(b *Project) *devdashpb.ProjectMutation {
 	milestones, deletedMilestones := genMilestoneDiffs(a.Milestones, b.Milestones)
 	diff().Milestones = milestones
 	diff().DeletedMilestones = deletedMilestones
+	if wm := genWorkflowDiff(a.Workflow, b.Workflow); wm != nil {
+		diff().Workflow = wm
+	}
+	labels, deletedLabels := genLabelDiffs(a.Labels, b.Labels)
+	diff().Labels = labels
+	diff().DeletedLabels = deletedLabels
+	repos, deletedRepos := genProjectRepoDiffs(a.Repos, b.Repos)
+	diff().Repos = repos
+	diff().DeletedRepos = deletedRepos
 	return ret
 }
 
@@ -360,6 +550,9 @@ func (a *Release) GenMutationDiff(b *Release) *devdashpb.ReleaseMutation {
 	if a.Closed != b.Closed {
 		diff().Closed = pbBool(b.Closed)
 	}
+	if a.TagPattern != b.TagPattern {
+		diff().TagPattern = b.TagPattern
+	}
 	return ret
 }
 
@@ -381,7 +574,7 @@ func genMilestoneDiffs(a, b map[string]*Milestone) (milestones []*devdashpb.Trac
 		if processed.has(id) {
 			continue
 		}
-		var ma Milestone
+		var ma *Milestone
 		milestoneDiff := ma.GenMutationDiff(mb)
 		milestones = append(milestones, milestoneDiff)
 	}
@@ -407,7 +600,7 @@ func (a *Milestone) GenMutationDiff(b *Milestone) *devdashpb.TrackerMilestone {
 	if a.Description != b.Description {
 		diff().Description = b.Description
 	}
-	if a.p.ID != b.p.ID {
+	if b.p != nil && (a.p == nil || a.p.ID != b.p.ID) {
 		diff().Project = b.p.ID
 	}
 	if a.Closed != b.Closed {
@@ -455,8 +648,8 @@ func (a *Issue) GenMutationDiff(b *Issue) *devdashpb.IssueMutation {
 	if a == nil {
 		a = emptyIssue
 	}
-	if a.p.ID != b.p.ID {
-		diff().Project = b.p.ID
+	if a.projectID() != b.projectID() {
+		diff().Project = b.projectID()
 	}
 	if a.Created != b.Created {
 		diff().Created = pbTimestamp(b.Created)
@@ -473,9 +666,8 @@ func (a *Issue) GenMutationDiff(b *Issue) *devdashpb.IssueMutation {
 	if a.Body != b.Body {
 		diff().Body = b.Body
 	}
-	if a.Owner != b.Owner {
-		diff().Owner = a.Owner.GenMutationDiff(b.Owner)
-
+	if um := genTrackerUserDiff(a.Owner, b.Owner); um != nil {
+		diff().Owner = um
 	}
 	if a.Status != b.Status {
 		diff().Status = b.Status
@@ -486,12 +678,28 @@ func (a *Issue) GenMutationDiff(b *Issue) *devdashpb.IssueMutation {
 	if a.ClosedAt != b.ClosedAt {
 		diff().ClosedAt = pbTimestamp(b.ClosedAt)
 	}
-	if *a.ClosedBy != *b.ClosedBy {
-		diff().ClosedBy = a.ClosedBy.GenMutationDiff(b.ClosedBy)
+	if um := genTrackerUserDiff(a.ClosedBy, b.ClosedBy); um != nil {
+		diff().ClosedBy = um
 	}
 	if a.URL != b.URL {
 		diff().Url = b.URL
 	}
+	if a.Priority != b.Priority {
+		diff().Priority = b.Priority
+	}
+	if a.Type != b.Type {
+		diff().Type = b.Type
+	}
+	if a.StoryPoints != b.StoryPoints {
+		diff().StoryPoints = &devdashpb.DoubleChange{Val: b.StoryPoints}
+	}
+	if a.Estimate != b.Estimate {
+		diff().Estimate = &devdashpb.Int64Change{Val: int64(b.Estimate / time.Second)}
+	}
+
+	customFields, deletedCustomFields := genCustomFieldDiffs(a.CustomFields, b.CustomFields)
+	diff().CustomFields = customFields
+	diff().DeletedCustomFields = deletedCustomFields
 
 	assignees, deletedAssignees := genTrackerUserDiffs(a.Assignees, b.Assignees)
 	diff().Assignees = assignees
@@ -505,13 +713,38 @@ func (a *Issue) GenMutationDiff(b *Issue) *devdashpb.IssueMutation {
 	diff().Labels = labels
 	diff().DeletedLabels = deletedLabels
 
+	links, deletedLinks := genIssueLinkDiffs(a.Links, b.Links)
+	diff().Links = links
+	diff().DeletedLinks = deletedLinks
+
 	// TODO commits
 
 	return ret
 }
 
+// projectID returns the ID of the issue's project, or "" if the issue
+// has no project.
+func (i *Issue) projectID() string {
+	if i.p == nil {
+		return ""
+	}
+	return i.p.ID
+}
+
 var emptyIssueTrackerUser = &IssueTrackerUser{}
 
+// genTrackerUserDiff returns the mutation to replace the user a with b.
+// It returns nil if b is nil or equal to a.
+func genTrackerUserDiff(a, b *IssueTrackerUser) *devdashpb.TrackerUser {
+	if b == nil {
+		return nil
+	}
+	if a != nil && a.ID != b.ID {
+		a = nil
+	}
+	return a.GenMutationDiff(b)
+}
+
 func (a *IssueTrackerUser) GenMutationDiff(b *IssueTrackerUser) *devdashpb.TrackerUser {
 	var ret *devdashpb.TrackerUser
 	diff := func() *devdashpb.TrackerUser {
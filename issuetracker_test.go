@@ -6,6 +6,7 @@ package devdashboard
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/urld/devdashboard/devdashpb"
@@ -168,6 +169,47 @@ func TestIssueMutation(t *testing.T) {
 	}
 }
 
+func TestIssueStatusHistory(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	day := func(n int) time.Time { return time.Date(2019, 1, n, 9, 0, 0, 0, time.UTC) }
+	for _, im := range []*devdashpb.IssueMutation{
+		{Id: "i1", Project: "ABC", Created: pbTimestamp(day(1)), Status: "New"},
+		{Id: "i1", Status: "In Progress", Updated: pbTimestamp(day(2))},
+		{Id: "i1", Status: "In Review"},
+		{Id: "i1", Status: "Done", Closed: pbBool(true), ClosedAt: pbTimestamp(day(4))},
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Issue: im}))
+	}
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	want := []IssueStatusChange{{"New", day(1)}, {"In Progress", day(2)}, {"In Review", time.Time{}}, {"Done", day(4)}}
+	check := func(i *Issue) {
+		t.Helper()
+		if len(i.StatusHistory) != len(want) {
+			t.Fatalf("expected status history %v, got %v", want, i.StatusHistory)
+		}
+		for n, sc := range i.StatusHistory {
+			if sc.Status != want[n].Status || !sc.Time.Equal(want[n].Time) {
+				t.Errorf("expected status history %v, got %v", want, i.StatusHistory)
+			}
+		}
+	}
+	check(c.Issues["i1"])
+
+	// untimed changes must stay untimed in snapshots
+	l = newLogger()
+	for _, im := range issueSnapshot(c.Issues["i1"]) {
+		checkErr(t, l.Log(&devdashpb.Mutation{Issue: im}))
+	}
+	l.end()
+	c = &Corpus{}
+	checkErr(t, c.Initialize(context.Background(), l))
+	check(c.Issues["i1"])
+}
+
 func TestMilestoneMutation(t *testing.T) {
 	l := newLogger()
 	c := &Corpus{}
@@ -349,6 +391,266 @@ func TestReleaseMutation(t *testing.T) {
 
 }
 
+func TestReleaseStatus(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	past, future := pbTimestamp(time.Now().AddDate(0, 0, -1)), pbTimestamp(time.Now().AddDate(0, 0, 1))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo:   "https://example.com/abc.git",
+			Commit: testCommit("aaaa1", "", "early release", 0),
+			Refs:   []*devdashpb.GitRef{{Ref: "refs/tags/r5.0", Sha1: "aaaa1"}},
+		},
+	}))
+	for _, rm := range []*devdashpb.ReleaseMutation{
+		{Id: "r1", Name: "planned", FreezeDate: future, ReleaseDate: future},
+		{Id: "r2", Name: "frozen", FreezeDate: past, ReleaseDate: future},
+		{Id: "r3", Name: "released", FreezeDate: past, ReleaseDate: past},
+		{Id: "r4", Name: "closed", FreezeDate: future, ReleaseDate: future, Closed: &devdashpb.BoolChange{Val: true}},
+		{Id: "r5", Name: "tagged", FreezeDate: future, ReleaseDate: future, TagPattern: "r5.*"},
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Release: rm}))
+	}
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	for id, want := range map[string]ReleaseStatus{
+		"r1": ReleasePlanned,
+		"r2": ReleaseFrozen,
+		"r3": ReleaseReleased,
+		"r4": ReleaseClosed,
+		"r5": ReleaseReleased,
+	} {
+		if got := c.Releases[id].Status(); got != want {
+			t.Errorf("release %s should be %s, got %s", id, want, got)
+		}
+	}
+}
+
+func TestWorkflowMutation(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Project: &devdashpb.ProjectMutation{
+			Id: "ABC",
+			Workflow: &devdashpb.Workflow{Statuses: []*devdashpb.WorkflowStatus{
+				{Name: "New", Category: devdashpb.StatusCategory_TODO},
+				{Name: "In Progress", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Review", Category: devdashpb.StatusCategory_IN_PROGRESS},
+				{Name: "Done", Category: devdashpb.StatusCategory_DONE},
+			}},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{Id: "i1", Project: "ABC", IssueKey: "ABC-1", Status: "Review"},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{Id: "i2", Project: "ABC", IssueKey: "ABC-2", Status: "Waiting"},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{Id: "i3", Project: "ABC", IssueKey: "ABC-3", Status: "Review", Closed: pbBool(true)},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	for id, want := range map[string]StatusCategory{
+		"i1": StatusInProgress,
+		"i2": StatusTodo,
+		"i3": StatusDone,
+	} {
+		if got := c.Issues[id].StatusCategory(); got != want {
+			t.Errorf("Issue %s should have status category %v, got %v", id, want, got)
+		}
+	}
+
+	var empty *Project
+	pm := empty.GenMutationDiff(c.Projects["ABC"])
+	if len(pm.Workflow.Statuses) != 4 {
+		t.Errorf("project diff should contain the workflow, got %v", pm.Workflow)
+	}
+}
+
+func TestIssueLinkMutation(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:       "i1",
+			Project:  "ABC",
+			IssueKey: "ABC-1",
+			Links: []*devdashpb.IssueLink{
+				{Issue: "i2", Type: devdashpb.IssueLinkType_BLOCKS},
+				{Issue: "i3", Type: devdashpb.IssueLinkType_PARENT},
+			},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{Id: "i2", Project: "ABC", IssueKey: "ABC-2"},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{Id: "i3", Project: "ABC", IssueKey: "ABC-3"},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	i1, i2, i3 := c.Issues["i1"], c.Issues["i2"], c.Issues["i3"]
+	if b := i2.OpenBlockers(); len(b) != 1 || b[0] != i1 {
+		t.Errorf("Issue i2 should be blocked by i1, got %v", b)
+	}
+	if i1.Parent() != i3 {
+		t.Error("Issue i3 should be the parent of i1")
+	}
+	if s := i3.Subtasks(); len(s) != 1 || s[0] != i1 {
+		t.Errorf("Issue i1 should be a subtask of i3, got %v", s)
+	}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{Id: "i1", Closed: pbBool(true)},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:           "i1",
+			DeletedLinks: []*devdashpb.IssueLink{{Issue: "i3", Type: devdashpb.IssueLinkType_PARENT}},
+		},
+	}))
+
+	l.end()
+	checkErr(t, c.Update(context.Background()))
+
+	if b := i2.OpenBlockers(); len(b) != 0 {
+		t.Errorf("Issue i2 should not have open blockers, got %v", b)
+	}
+	if len(i2.BlockedBy()) != 1 {
+		t.Error("Issue i2 should still be blocked by the closed issue i1")
+	}
+	if i1.Parent() != nil || len(i3.Subtasks()) != 0 {
+		t.Error("parent link should have been removed")
+	}
+}
+
+func TestLabelMutation(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Project: &devdashpb.ProjectMutation{
+			Id: "ABC",
+			Labels: []*devdashpb.Label{
+				{Id: "l1", Name: "bug", Color: "e11d21", Description: "Something isn't working"},
+				{Id: "l2", Name: "wontfix", Color: "ffffff"},
+			},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:       "i1",
+			Project:  "ABC",
+			IssueKey: "ABC-1",
+			Labels:   []*devdashpb.TrackerLabel{{Name: "bug"}, {Name: "unknown"}},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Project: &devdashpb.ProjectMutation{
+			Id:            "ABC",
+			Labels:        []*devdashpb.Label{{Id: "l1", Color: "#FBCA04"}},
+			DeletedLabels: []string{"l2"},
+		},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	abc := c.Projects["ABC"]
+	if len(abc.Labels) != 1 {
+		t.Fatalf("Project ABC should have 1 label, got %d", len(abc.Labels))
+	}
+	bug := abc.Label("bug")
+	if bug == nil || bug.Description != "Something isn't working" {
+		t.Fatalf("label bug should keep its description, got %v", bug)
+	}
+	if bug.CSSColor() != "#fbca04" || bug.CSSTextColor() != "#000000" {
+		t.Errorf("unexpected label colors %s on %s", bug.CSSTextColor(), bug.CSSColor())
+	}
+
+	labels := c.Issues["i1"].LabelList()
+	if len(labels) != 2 || labels[0] != bug || labels[1].Name != "unknown" {
+		t.Errorf("unexpected issue labels %v", labels)
+	}
+	if labels[1].CSSColor() != "#ededed" {
+		t.Errorf("unknown label should have the default color, got %s", labels[1].CSSColor())
+	}
+}
+
+func TestIssueFieldsMutation(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:          "i1",
+			Project:     "ABC",
+			IssueKey:    "ABC-1",
+			Title:       "crash on startup",
+			Priority:    "High",
+			Type:        "bug",
+			StoryPoints: &devdashpb.DoubleChange{Val: 3},
+			Estimate:    &devdashpb.Int64Change{Val: 7200},
+			CustomFields: []*devdashpb.CustomField{
+				{Key: "component", Value: &devdashpb.CustomField_StringVal{StringVal: "server"}},
+				{Key: "severity", Value: &devdashpb.CustomField_NumberVal{NumberVal: 2}},
+			},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:       "i2",
+			Project:  "ABC",
+			IssueKey: "ABC-2",
+			Title:    "update readme",
+			Priority: "P4",
+			Type:     "task",
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Issue: &devdashpb.IssueMutation{
+			Id:                  "i1",
+			DeletedCustomFields: []string{"severity"},
+		},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	i1 := c.Issues["i1"]
+	if i1.StoryPoints != 3 || i1.Estimate != 2*time.Hour {
+		t.Errorf("unexpected story points %v and estimate %v", i1.StoryPoints, i1.Estimate)
+	}
+	if len(i1.CustomFields) != 1 || i1.CustomFields["component"].String() != "server" {
+		t.Errorf("unexpected custom fields %v", i1.CustomFields)
+	}
+
+	m := (*Issue)(nil).GenMutationDiff(i1)
+	if m.Priority != "High" || m.Type != "bug" || m.Estimate.GetVal() != 7200 ||
+		len(m.CustomFields) != 1 {
+		t.Errorf("unexpected mutation diff %v", m)
+	}
+
+	issues := c.SearchIssues(ParseQuery("sort:priority"))
+	if len(issues) != 2 || issues[0] != i1 {
+		t.Errorf("issue i1 should be ranked first, got %v", issues)
+	}
+	issues = c.SearchIssues(ParseQuery("crash type:bug component:server"))
+	if len(issues) != 1 || issues[0] != i1 {
+		t.Errorf("search should only find issue i1, got %v", issues)
+	}
+}
+
 func checkErr(t *testing.T, err error) {
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err.Error())
@@ -0,0 +1,169 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// Label is a label of a project. Issues refer to labels by name.
+type Label struct {
+	p *Project
+
+	ID          string
+	Name        string
+	Color       string // hex RGB color, such as "e11d21"
+	Description string
+}
+
+const defaultLabelColor = "ededed"
+
+// rgb returns the red, green and blue components of the label color.
+func (l *Label) rgb() (r, g, b int64, ok bool) {
+	c := strings.TrimPrefix(l.Color, "#")
+	if len(c) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(c, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int64(v >> 16), int64(v >> 8 & 0xff), int64(v & 0xff), true
+}
+
+// CSSColor returns the label color in CSS notation. Labels without a
+// valid color get a light grey.
+func (l *Label) CSSColor() string {
+	if _, _, _, ok := l.rgb(); !ok {
+		return "#" + defaultLabelColor
+	}
+	return "#" + strings.ToLower(strings.TrimPrefix(l.Color, "#"))
+}
+
+// CSSTextColor returns black or white, whichever is easier to read on the
+// label color.
+func (l *Label) CSSTextColor() string {
+	r, g, b, ok := l.rgb()
+	if !ok {
+		return "#000000"
+	}
+	// perceived brightness, see https://www.w3.org/TR/AERT/#color-contrast
+	if (r*299+g*587+b*114)/1000 > 128 {
+		return "#000000"
+	}
+	return "#ffffff"
+}
+
+// Label returns the project label with the given name, or nil if the
+// project does not know such a label.
+func (p *Project) Label(name string) *Label {
+	for _, l := range p.Labels {
+		if l.Name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// LabelList returns the labels of the issue sorted by name. Labels unknown
+// to the issue's project are returned without color and description.
+func (i *Issue) LabelList() []*Label {
+	labels := make([]*Label, 0, len(i.Labels))
+	for name := range i.Labels {
+		var l *Label
+		if i.p != nil {
+			l = i.p.Label(name)
+		}
+		if l == nil {
+			l = &Label{p: i.p, Name: name}
+		}
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
+
+// HasLabel reports whether the issue has the named label.
+func (i *Issue) HasLabel(name string) bool {
+	_, ok := i.Labels[name]
+	return ok
+}
+
+func (c *Corpus) processLabelMutation(p *Project, lm *devdashpb.Label) {
+	l, ok := p.Labels[lm.Id]
+	if !ok {
+		// new label
+		l = &Label{
+			p:  p,
+			ID: lm.Id,
+		}
+		if p.Labels == nil {
+			p.Labels = make(map[string]*Label)
+		}
+		p.Labels[lm.Id] = l
+	}
+	if lm.Name != "" {
+		l.Name = lm.Name
+	}
+	if lm.Color != "" {
+		l.Color = lm.Color
+	}
+	if lm.Description != "" {
+		l.Description = lm.Description
+	}
+}
+
+var emptyLabel = &Label{}
+
+func (a *Label) GenMutationDiff(b *Label) *devdashpb.Label {
+	var ret *devdashpb.Label // lazily initialized by diff
+	diff := func() *devdashpb.Label {
+		if ret == nil {
+			ret = &devdashpb.Label{Id: b.ID}
+		}
+		return ret
+	}
+	if a == nil {
+		// new labels need their ID, even without any other field
+		diff()
+		a = emptyLabel
+	}
+	if a.Name != b.Name {
+		diff().Name = b.Name
+	}
+	if a.Color != b.Color {
+		diff().Color = b.Color
+	}
+	if a.Description != b.Description {
+		diff().Description = b.Description
+	}
+	return ret
+}
+
+func genLabelDiffs(a, b map[string]*Label) (labels []*devdashpb.Label, deletedLabels []string) {
+	for id, la := range a {
+		if _, ok := b[id]; !ok {
+			deletedLabels = append(deletedLabels, id)
+			continue
+		}
+		if labelDiff := la.GenMutationDiff(b[id]); labelDiff != nil {
+			labels = append(labels, labelDiff)
+		}
+	}
+	for id, lb := range b {
+		if _, ok := a[id]; ok {
+			continue
+		}
+		var la *Label
+		if labelDiff := la.GenMutationDiff(lb); labelDiff != nil {
+			labels = append(labels, labelDiff)
+		}
+	}
+	return
+}
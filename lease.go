@@ -0,0 +1,86 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/urld/devdashboard/reclog"
+)
+
+// LockFile is the name of the lock file in the log directory. Only the
+// DiskMutationLogger holding the lock, the lease, appends to the log.
+// Reading the log, writing snapshots and compressing sealed log files need
+// no lease.
+const LockFile = "devdashboard.lock"
+
+// A LockedError is returned by a DiskMutationLogger if another process
+// holds the lease of its directory.
+type LockedError struct {
+	Dir   string
+	Owner string // as written to the lock file by the last owner, if known
+}
+
+func (e *LockedError) Error() string {
+	if e.Owner == "" {
+		return fmt.Sprintf("mutation log %s is locked by another process", e.Dir)
+	}
+	return fmt.Sprintf("mutation log %s is locked by another process, last acquired by %s", e.Dir, e.Owner)
+}
+
+// Acquire takes the lease of the log directory, or returns a *LockedError
+// if another process holds it. Log acquires the lease implicitly; Acquire
+// lets writers fail early instead. The lease is held until Release is
+// called or the process exits.
+func (d *DiskMutationLogger) Acquire() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.acquire()
+}
+
+// acquire is like Acquire, but d.mu must be held.
+func (d *DiskMutationLogger) acquire() error {
+	if d.lease != nil {
+		return nil
+	}
+	f, err := os.OpenFile(d.path(LockFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if err := reclog.TryLockFile(f); err != nil {
+		if err == reclog.ErrLocked {
+			owner, _ := ioutil.ReadAll(f)
+			err = &LockedError{Dir: d.directory, Owner: strings.TrimSpace(string(owner))}
+		}
+		f.Close()
+		return err
+	}
+	// The owner is informational, so errors are ignored.
+	host, _ := os.Hostname()
+	f.Truncate(0)
+	fmt.Fprintf(f, "pid %d on %s\n", os.Getpid(), host)
+
+	// Another process may have appended to the log since d looked at it.
+	d.current = ""
+	d.recovered = nil
+	d.lease = f
+	return nil
+}
+
+// Release gives up the lease of the log directory.
+func (d *DiskMutationLogger) Release() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.lease == nil {
+		return nil
+	}
+	d.lease.Truncate(0)
+	err := d.lease.Close()
+	d.lease = nil
+	return err
+}
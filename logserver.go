@@ -0,0 +1,215 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogSegment is a mutation log file served by a LogHandler.
+type LogSegment struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"` // hex sha256 of the first Size bytes
+}
+
+// logWaitTimeout is the maximum time a LogHandler waits for new records.
+const logWaitTimeout = time.Minute
+
+// logPollInterval is how often a waiting LogHandler checks for records
+// logged by other processes.
+const logPollInterval = 2 * time.Second
+
+// LogHandler returns a handler serving the mutation log files of d, which
+// are replicated by NetworkMutationSource. The handler must be registered
+// for "/logs" and "/logs/":
+//
+//	GET /logs               lists all log files as JSON array of LogSegment
+//	GET /logs?waitsizenot=N lists all log files as soon as their total size is
+//	                        not N, or after a minute
+//	GET /logs/NAME          returns the named log file, supporting range requests
+func LogHandler(d *DiskMutationLogger) http.Handler {
+	return &logHandler{d: d, sums: make(map[string]*prefixHash)}
+}
+
+type logHandler struct {
+	d *DiskMutationLogger
+
+	mu   sync.Mutex
+	sums map[string]*prefixHash // file name -> checksum
+}
+
+func (h *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	p := strings.TrimSuffix(r.URL.Path, "/")
+	if strings.HasSuffix(p, "/logs") || p == "" {
+		h.serveList(w, r)
+		return
+	}
+	h.serveFile(w, r, path.Base(p))
+}
+
+func (h *logHandler) serveList(w http.ResponseWriter, r *http.Request) {
+	segs, err := h.segments()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if s := r.FormValue("waitsizenot"); s != "" {
+		notSize, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid waitsizenot: "+s, http.StatusBadRequest)
+			return
+		}
+		timeout := time.NewTimer(logWaitTimeout)
+		defer timeout.Stop()
+		poll := time.NewTicker(logPollInterval)
+		defer poll.Stop()
+	wait:
+		for totalSize(segs) == notSize {
+			select {
+			case <-h.d.waitChange():
+			case <-poll.C:
+			case <-timeout.C:
+				break wait
+			case <-r.Context().Done():
+				return
+			}
+			if segs, err = h.segments(); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(segs)
+}
+
+func (h *logHandler) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	var (
+		found bool
+		fi    os.FileInfo
+	)
+	err := h.d.ForeachFile(func(fullPath string, f os.FileInfo) error {
+		if f.Name() == name {
+			found, fi = true, f
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(h.d.path(name))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", "application/octet-stream")
+	// only serve what was logged when the file was listed, so a record
+	// which is appended concurrently is not served partially.
+	http.ServeContent(w, r, name, fi.ModTime(), io.NewSectionReader(f, 0, fi.Size()))
+}
+
+// segments lists all log files with their checksums.
+func (h *logHandler) segments() ([]LogSegment, error) {
+	segs := []LogSegment{}
+	err := h.d.ForeachFile(func(fullPath string, fi os.FileInfo) error {
+		sum, err := h.checksum(fullPath, fi)
+		if err != nil {
+			return err
+		}
+		segs = append(segs, LogSegment{Name: fi.Name(), Size: fi.Size(), SHA256: sum})
+		return nil
+	})
+	return segs, err
+}
+
+// checksum returns the sha256 of the first fi.Size() bytes of the file.
+// Only records appended since the last call are hashed.
+func (h *logHandler) checksum(fullPath string, fi os.FileInfo) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ph, ok := h.sums[fi.Name()]
+	if !ok {
+		ph = new(prefixHash)
+		h.sums[fi.Name()] = ph
+	}
+	return ph.sum(fullPath, fi.Size())
+}
+
+// prefixHash is the sha256 of the first bytes of a log file. Log files are
+// only appended to, so the hash is extended by the appended bytes instead
+// of hashing the whole file again.
+type prefixHash struct {
+	fi   os.FileInfo // of the hashed file
+	size int64       // number of hashed bytes
+	h    hash.Hash   // nil to start over
+	hex  string      // hex sha256 of the hashed bytes
+}
+
+// sum returns the hex sha256 of the first size bytes of the named file. It
+// starts over if the file was replaced or truncated since the last call.
+func (ph *prefixHash) sum(filename string, size int64) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if ph.h == nil || !os.SameFile(ph.fi, fi) || size < ph.size {
+		ph.h, ph.size = sha256.New(), 0
+	} else if size == ph.size {
+		return ph.hex, nil
+	}
+	ph.fi = fi
+	if _, err := f.Seek(ph.size, io.SeekStart); err != nil {
+		ph.h = nil
+		return "", err
+	}
+	if _, err := io.CopyN(ph.h, f, size-ph.size); err != nil {
+		ph.h = nil
+		return "", err
+	}
+	ph.size = size
+	ph.hex = hex.EncodeToString(ph.h.Sum(nil))
+	return ph.hex, nil
+}
+
+// reset makes the next call of sum hash the file from the beginning, as it
+// is rewritten.
+func (ph *prefixHash) reset() {
+	ph.h = nil
+}
+
+func totalSize(segs []LogSegment) int64 {
+	var size int64
+	for _, seg := range segs {
+		size += seg.Size
+	}
+	return size
+}
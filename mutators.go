@@ -42,3 +42,15 @@ type MutationStreamEvent struct {
 type MutationLogger interface {
 	Log(*devdashpb.Mutation) error
 }
+
+// A Syncer fetches the changes of an external source, such as an issue
+// tracker or a git repository, and logs them as mutations.
+type Syncer interface {
+	// Name identifies the source, such as the URL of a git repository.
+	Name() string
+
+	// Sync logs all changes since the previous call to Sync. On the
+	// first call, the corpus is used to look up what is already known.
+	// The corpus may be nil, and is read while holding its read lock.
+	Sync(ctx context.Context, c *Corpus, l MutationLogger) error
+}
@@ -0,0 +1,224 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NetworkMutationSource replicates the mutation log served by a LogHandler
+// into a local cache directory and yields the mutations from there. Only
+// new records are downloaded, so the cache directory can be reused across
+// runs.
+type NetworkMutationSource struct {
+	server   string // base URL of the LogHandler, without "/logs"
+	cacheDir string
+	client   *http.Client
+
+	disk     *DiskMutationLogger    // reads the cache directory
+	lastSize int64                  // total size of the log at the last sync, -1 before
+	sums     map[string]*prefixHash // file name -> checksum of the cached file
+}
+
+// NewNetworkMutationSource returns a MutationSource replicating the log of
+// the server, such as "https://devdash.example.com", into cacheDir.
+func NewNetworkMutationSource(server, cacheDir string) *NetworkMutationSource {
+	return &NetworkMutationSource{
+		server:   strings.TrimSuffix(server, "/"),
+		cacheDir: cacheDir,
+		client:   http.DefaultClient,
+		disk:     NewDiskMutationLogger(cacheDir),
+		lastSize: -1,
+		sums:     make(map[string]*prefixHash),
+	}
+}
+
+// GetMutations downloads all new records of the server's log and yields
+// their mutations. Except for the first call, it waits for new records
+// before returning anything.
+func (ns *NetworkMutationSource) GetMutations(ctx context.Context) <-chan MutationStreamEvent {
+	ch := make(chan MutationStreamEvent, 50)
+	go func() {
+		err := ns.sync(ctx)
+		if err == nil {
+			err = ns.disk.sendMutations(ctx, ch)
+		}
+		final := MutationStreamEvent{Err: err}
+		if err == nil {
+			final.End = true
+		}
+		select {
+		case ch <- final:
+		case <-ctx.Done():
+		}
+	}()
+	return ch
+}
+
+// sync downloads all new records into the cache directory.
+func (ns *NetworkMutationSource) sync(ctx context.Context) error {
+	if err := os.MkdirAll(ns.cacheDir, 0700); err != nil {
+		return err
+	}
+	segs, err := ns.list(ctx)
+	if err != nil {
+		return err
+	}
+	for _, seg := range segs {
+		if err := ns.syncSegment(ctx, seg); err != nil {
+			return err
+		}
+		if strings.HasSuffix(seg.Name, compressedSuffix) {
+			// the server replaced the uncompressed file
+			os.Remove(segmentPath(filepath.Join(ns.cacheDir, seg.Name)))
+			delete(ns.sums, segmentPath(seg.Name))
+		}
+	}
+	ns.lastSize = totalSize(segs)
+	return nil
+}
+
+// list returns the log files of the server. If the size of the log is
+// known from a previous sync, list waits until it changed.
+func (ns *NetworkMutationSource) list(ctx context.Context) ([]LogSegment, error) {
+	u := ns.server + "/logs"
+	if ns.lastSize >= 0 {
+		u += "?waitsizenot=" + fmt.Sprint(ns.lastSize)
+	}
+	res, err := ns.get(ctx, u, "")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	var segs []LogSegment
+	if err := json.NewDecoder(res.Body).Decode(&segs); err != nil {
+		return nil, fmt.Errorf("decoding log list of %s: %v", ns.server, err)
+	}
+	for _, seg := range segs {
+		if seg.Name != filepath.Base(seg.Name) || !strings.HasPrefix(seg.Name, "devdashboard-") {
+			return nil, fmt.Errorf("invalid log file name %q", seg.Name)
+		}
+	}
+	return segs, nil
+}
+
+// syncSegment downloads the missing part of a log file. Files which do
+// not match the checksum of the server are downloaded again. Only the
+// downloaded part is hashed, as long as the cached part was verified.
+func (ns *NetworkMutationSource) syncSegment(ctx context.Context, seg LogSegment) error {
+	if seg.Size == 0 {
+		return nil
+	}
+	local := filepath.Join(ns.cacheDir, seg.Name)
+	ph, ok := ns.sums[seg.Name]
+	if !ok {
+		ph = new(prefixHash)
+		ns.sums[seg.Name] = ph
+	}
+	var size int64
+	if fi, err := os.Stat(local); err == nil {
+		size = fi.Size()
+	}
+	if size == seg.Size {
+		sum, err := ph.sum(local, size)
+		if err != nil || sum == seg.SHA256 {
+			return err
+		}
+	}
+	if size >= seg.Size {
+		// the file was replaced on the server, start over:
+		size = 0
+	}
+	if size < ph.size {
+		ph.reset()
+	}
+	if err := ns.download(ctx, local, seg, size); err != nil {
+		ph.reset()
+		return err
+	}
+	sum, err := ph.sum(local, seg.Size)
+	if err != nil {
+		return err
+	}
+	if sum == seg.SHA256 {
+		return nil
+	}
+	if size == 0 {
+		return fmt.Errorf("checksum mismatch of %s", seg.Name)
+	}
+	// the cached part was corrupt:
+	ph.reset()
+	if err := ns.download(ctx, local, seg, 0); err != nil {
+		return err
+	}
+	if sum, err = ph.sum(local, seg.Size); err == nil && sum != seg.SHA256 {
+		err = fmt.Errorf("checksum mismatch of %s", seg.Name)
+	}
+	return err
+}
+
+// download fetches the bytes from off to seg.Size of the log file and
+// writes them to the local file at off.
+func (ns *NetworkMutationSource) download(ctx context.Context, local string, seg LogSegment, off int64) error {
+	res, err := ns.get(ctx, ns.server+"/logs/"+url.PathEscape(seg.Name), fmt.Sprintf("bytes=%d-%d", off, seg.Size-1))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if off > 0 && res.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("%s: server does not support range requests", seg.Name)
+	}
+	_, err = os.Stat(local)
+	replace := off == 0 && err == nil
+	f, err := os.OpenFile(local, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(off); err != nil {
+		f.Close()
+		return err
+	}
+	if replace {
+		// the file is read from the beginning again:
+		ns.disk.resetFile(local)
+	}
+	if _, err := f.Seek(off, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := io.CopyN(f, res.Body, seg.Size-off); err != nil {
+		f.Close()
+		return fmt.Errorf("downloading %s: %v", seg.Name, err)
+	}
+	return f.Close()
+}
+
+func (ns *NetworkMutationSource) get(ctx context.Context, u, byteRange string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+	res, err := ns.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		res.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", u, res.Status)
+	}
+	return res, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/urld/devdashboard/devdashpb"
+	"github.com/urld/devdashboard/reclog"
+)
+
+func TestNetworkMutationSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	d := NewDiskMutationLogger(filepath.Join(dir, "server"))
+	checkErr(t, os.MkdirAll(d.directory, 0700))
+	logProject := func(id string) {
+		checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: id, Name: id}}))
+	}
+	logProject("ABC")
+
+	mux := http.NewServeMux()
+	mux.Handle("/logs", LogHandler(d))
+	mux.Handle("/logs/", LogHandler(d))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	cache := filepath.Join(dir, "cache")
+	c := new(Corpus)
+	checkErr(t, c.Initialize(ctx, NewNetworkMutationSource(srv.URL, cache)))
+	if c.Projects["ABC"] == nil {
+		t.Fatalf("project ABC not replicated")
+	}
+
+	// Update waits for the next record:
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		logProject("DEF")
+	}()
+	checkErr(t, c.Update(ctx))
+	if c.Projects["DEF"] == nil {
+		t.Fatalf("project DEF not replicated")
+	}
+
+	// a corrupt cache is downloaded again:
+	var name string
+	checkErr(t, d.ForeachFile(func(fullPath string, fi os.FileInfo) error {
+		name = fi.Name()
+		return nil
+	}))
+	data, err := ioutil.ReadFile(filepath.Join(cache, name))
+	checkErr(t, err)
+	data[len(data)-1] ^= 0xff
+	checkErr(t, ioutil.WriteFile(filepath.Join(cache, name), data, 0600))
+	logProject("GHI")
+
+	c = new(Corpus)
+	checkErr(t, c.Initialize(ctx, NewNetworkMutationSource(srv.URL, cache)))
+	for _, id := range []string{"ABC", "DEF", "GHI"} {
+		if p := c.Projects[id]; p == nil || p.Name != id {
+			t.Errorf("project %s not replicated: %v", id, p)
+		}
+	}
+}
+
+func TestNetworkMutationSourceCompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	d := NewDiskMutationLogger(filepath.Join(dir, "server"))
+	checkErr(t, os.MkdirAll(d.directory, 0700))
+	data, err := proto.Marshal(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "ABC"}})
+	checkErr(t, err)
+	checkErr(t, reclog.AppendRecordToFile(d.path("devdashboard-2019-01-01.mutlog"), data))
+	checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "DEF"}}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/logs", LogHandler(d))
+	mux.Handle("/logs/", LogHandler(d))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cache := filepath.Join(dir, "cache")
+	ns := NewNetworkMutationSource(srv.URL, cache)
+	if n := countMutations(t, ns); n != 2 {
+		t.Fatalf("expected 2 mutations, got %d", n)
+	}
+	_, err = d.Compress()
+	checkErr(t, err)
+	checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "GHI"}}))
+	if n := countMutations(t, ns); n != 1 {
+		t.Errorf("only the new mutation should be sent, got %d", n)
+	}
+	if _, err := os.Stat(filepath.Join(cache, "devdashboard-2019-01-01.mutlog")); !os.IsNotExist(err) {
+		t.Errorf("the replaced uncompressed file should be removed from the cache")
+	}
+
+	c := new(Corpus)
+	checkErr(t, c.Initialize(context.Background(), NewDiskMutationLogger(cache)))
+	if len(c.Projects) != 3 {
+		t.Errorf("expected 3 projects, got %v", c.Projects)
+	}
+}
+
+func TestPrefixHash(t *testing.T) {
+	f, err := ioutil.TempFile("", "devdashboard")
+	checkErr(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	var ph prefixHash
+	check := func(size int64, want string) {
+		t.Helper()
+		sum, err := ph.sum(f.Name(), size)
+		checkErr(t, err)
+		if wantSum := fmt.Sprintf("%x", sha256.Sum256([]byte(want))); sum != wantSum {
+			t.Errorf("sum of %q = %s, want %s", want, sum, wantSum)
+		}
+	}
+	_, err = f.WriteString("abc")
+	checkErr(t, err)
+	check(3, "abc")
+	_, err = f.WriteString("def")
+	checkErr(t, err)
+	check(4, "abcd")
+	check(6, "abcdef")
+	if ph.size != 6 {
+		t.Errorf("expected 6 hashed bytes, got %d", ph.size)
+	}
+
+	// truncated files are hashed again:
+	checkErr(t, f.Truncate(2))
+	check(2, "ab")
+
+	// rewritten files are hashed again after reset:
+	_, err = f.WriteAt([]byte("xy"), 0)
+	checkErr(t, err)
+	ph.reset()
+	check(2, "xy")
+}
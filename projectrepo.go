@@ -0,0 +1,192 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// ProjectRepo is a git repository, or a set of directories within a
+// repository, holding the code of a project.
+type ProjectRepo struct {
+	p *Project
+
+	URL   string
+	Paths []string // directories within the repository, all if empty
+}
+
+// GitRepo returns the repository, or nil if it is not known.
+func (pr *ProjectRepo) GitRepo() *GitRepo {
+	return pr.p.c.GitRepos[pr.URL]
+}
+
+// ContainsFile reports whether the file is part of the project.
+func (pr *ProjectRepo) ContainsFile(file string) bool {
+	if len(pr.Paths) == 0 {
+		return true
+	}
+	for _, dir := range pr.Paths {
+		dir = strings.Trim(path.Clean(dir), "/")
+		if dir == "." || dir == "" || file == dir || strings.HasPrefix(file, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RepoList returns the repositories of the project sorted by URL.
+func (p *Project) RepoList() []*ProjectRepo {
+	ret := make([]*ProjectRepo, 0, len(p.Repos))
+	for _, pr := range p.Repos {
+		ret = append(ret, pr)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].URL < ret[j].URL })
+	return ret
+}
+
+// hasRepo reports whether the repository holds code of the project.
+// Projects without repositories use all repositories.
+func (p *Project) hasRepo(r *GitRepo) bool {
+	_, ok := p.Repos[r.URL]
+	return ok || len(p.Repos) == 0
+}
+
+// ContainsFile reports whether the file of the repository is part of the
+// project. Projects without repositories contain all files of all
+// repositories.
+func (p *Project) ContainsFile(r *GitRepo, file string) bool {
+	if len(p.Repos) == 0 {
+		return true
+	}
+	pr, ok := p.Repos[r.URL]
+	return ok && pr.ContainsFile(file)
+}
+
+// ContainsCommit reports whether the commit changes any file of the
+// project. Projects without repositories contain all commits, and commits
+// without diff tree are contained if the project holds their whole
+// repository.
+func (p *Project) ContainsCommit(gc *GitCommit) bool {
+	if len(p.Repos) == 0 {
+		return true
+	}
+	pr, ok := p.Repos[gc.r.URL]
+	if !ok {
+		return false
+	}
+	if len(pr.Paths) == 0 {
+		return true
+	}
+	for file := range gc.DiffTree {
+		if pr.ContainsFile(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// Commits returns all commits of the project's repositories which change
+// files of the project, newest first.
+func (p *Project) Commits() []*GitCommit {
+	var ret []*GitCommit
+	for _, r := range p.c.GitRepos {
+		if !p.hasRepo(r) {
+			continue
+		}
+		for _, gc := range r.commits {
+			if p.ContainsCommit(gc) {
+				ret = append(ret, gc)
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		a, b := ret[i], ret[j]
+		if !a.CommitTime.Equal(b.CommitTime) {
+			return a.CommitTime.After(b.CommitTime)
+		}
+		return a.Sha1 < b.Sha1
+	})
+	return ret
+}
+
+// acceptsCommit reports whether the commit may be linked to the issue,
+// which is the case if it changes files of the issue's project.
+func (i *Issue) acceptsCommit(gc *GitCommit) bool {
+	return i.p == nil || i.p.ContainsCommit(gc)
+}
+
+// relinkCommits links the issue to all commits mentioning its issue key
+// which are accepted by the issue's project.
+func (i *Issue) relinkCommits() {
+	i.Commits = nil
+	for _, gc := range i.c.commitsByIssueKey[i.IssueKey] {
+		if i.acceptsCommit(gc) {
+			i.linkCommit(gc)
+		}
+	}
+}
+
+// relinkCommit updates the links of the commit to all issues it mentions,
+// after its diff tree changed.
+func (c *Corpus) relinkCommit(gc *GitCommit) {
+	for _, key := range issueKeyRx.FindAllString(gc.Msg, -1) {
+		i, ok := c.issuesByKey[key]
+		if !ok {
+			continue
+		}
+		if i.acceptsCommit(gc) {
+			i.linkCommit(gc)
+		} else {
+			delete(i.Commits, gc.Sha1)
+		}
+	}
+}
+
+func (c *Corpus) processProjectRepoMutation(p *Project, rm *devdashpb.ProjectRepo) {
+	pr, ok := p.Repos[rm.Repo]
+	if !ok {
+		// new repo
+		pr = &ProjectRepo{
+			p:   p,
+			URL: rm.Repo,
+		}
+		if p.Repos == nil {
+			p.Repos = make(map[string]*ProjectRepo)
+		}
+		p.Repos[rm.Repo] = pr
+	}
+	pr.Paths = rm.Paths
+}
+
+func (a *ProjectRepo) GenMutationDiff(b *ProjectRepo) *devdashpb.ProjectRepo {
+	if a != nil && stringsEqual(a.Paths, b.Paths) {
+		return nil
+	}
+	return &devdashpb.ProjectRepo{Repo: b.URL, Paths: b.Paths}
+}
+
+func genProjectRepoDiffs(a, b map[string]*ProjectRepo) (repos []*devdashpb.ProjectRepo, deletedRepos []string) {
+	for url, ra := range a {
+		if _, ok := b[url]; !ok {
+			deletedRepos = append(deletedRepos, url)
+			continue
+		}
+		if repoDiff := ra.GenMutationDiff(b[url]); repoDiff != nil {
+			repos = append(repos, repoDiff)
+		}
+	}
+	for url, rb := range b {
+		if _, ok := a[url]; ok {
+			continue
+		}
+		var ra *ProjectRepo
+		repos = append(repos, ra.GenMutationDiff(rb))
+	}
+	return
+}
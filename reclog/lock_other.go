@@ -0,0 +1,26 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package reclog
+
+import "os"
+
+// LockFile does nothing on this platform, where files cannot be locked.
+// Processes sharing a log must be coordinated otherwise.
+func LockFile(f *os.File) error {
+	return nil
+}
+
+// TryLockFile does nothing on this platform, see LockFile.
+func TryLockFile(f *os.File) error {
+	return nil
+}
+
+// UnlockFile does nothing on this platform, see LockFile.
+func UnlockFile(f *os.File) error {
+	return nil
+}
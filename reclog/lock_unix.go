@@ -0,0 +1,48 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package reclog
+
+import (
+	"os"
+	"syscall"
+)
+
+// LockFile places an advisory exclusive lock on f, waiting until other
+// processes released theirs. The lock is released by UnlockFile or by
+// closing f.
+func LockFile(f *os.File) error {
+	return flock(f, syscall.LOCK_EX)
+}
+
+// TryLockFile is like LockFile, but returns ErrLocked instead of waiting
+// if another process holds the lock.
+func TryLockFile(f *os.File) error {
+	err := flock(f, syscall.LOCK_EX|syscall.LOCK_NB)
+	if pe, ok := err.(*os.PathError); ok && pe.Err == syscall.EWOULDBLOCK {
+		return ErrLocked
+	}
+	return err
+}
+
+// UnlockFile releases the lock on f.
+func UnlockFile(f *os.File) error {
+	return flock(f, syscall.LOCK_UN)
+}
+
+func flock(f *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return &os.PathError{Op: "flock", Path: f.Name(), Err: err}
+		}
+		return nil
+	}
+}
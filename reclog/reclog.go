@@ -11,7 +11,9 @@ package reclog
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
 	"os"
 	"strconv"
@@ -20,17 +22,38 @@ import (
 // The reclog format is as follows:
 //
 // The log is a series of binary blobs. Each record begins with the
-// variably-lengthed prefix "REC@XXX+YYY=" where the 0+ XXXX digits
-// are the hex offset on disk (where the 'R' on disk is written) and
-// the 0+ YYY digits are the hex length of the blob. After the YYY
-// digits there is a '=' byte before the YYY bytes of blob. There is
-// no record footer.
+// variably-lengthed prefix "REC2@XXX+YYY,ZZZ=" where the 0+ XXXX digits
+// are the hex offset on disk (where the 'R' on disk is written), the 0+
+// YYY digits are the hex length of the blob and the 0+ ZZZ digits are the
+// hex CRC-32C (Castagnoli) checksum of the blob. After the ZZZ digits
+// there is a '=' byte before the YYY bytes of blob. There is no record
+// footer.
+//
+// Records of the original format "REC@XXX+YYY=" have no checksum. They
+// are still read, so old logs remain valid.
+var (
+	headerPrefix  = []byte("REC@")
+	header2Prefix = []byte("REC2@")
+	headerSuffix  = []byte("=")
+	plus          = []byte("+")
+	comma         = []byte(",")
+)
+
+// maxHeaderLen is the length of the longest valid header.
+const maxHeaderLen = len("REC2@+,=") + 16 + 16 + 8
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Reasons of a TruncatedError.
 var (
-	headerPrefix = []byte("REC@")
-	headerSuffix = []byte("=")
-	plus         = []byte("+")
+	errTornHeader   = errors.New("truncated header")
+	errTornRecord   = errors.New("truncated record")
+	errTornChecksum = errors.New("checksum mismatch in last record")
 )
 
+// ErrLocked is returned by TryLockFile if another process holds the lock.
+var ErrLocked = errors.New("file is locked by another process")
+
 // RecordCallback is the callback signature accepted by
 // ForeachFileRecord and ForeachRecord, which read the mutation log
 // format used by DiskMutationLogger.
@@ -39,8 +62,9 @@ var (
 // hdr and bytes are only valid until the function returns
 // and must not be retained.
 //
-// hdr is the record header, in the form "REC@c765c9a+1d3=" (REC@ <hex
-// offset> + <hex len(rec)> + '=').
+// hdr is the record header, in the form "REC2@c765c9a+1d3,5e0bf5ad="
+// (REC2@ <hex offset> + <hex len(rec)> , <hex crc32c(rec)> '='), or
+// "REC@c765c9a+1d3=" for records without checksum.
 //
 // rec is the proto3 binary marshalled representation of
 // *maintpb.Mutation.
@@ -48,6 +72,18 @@ var (
 // If the callback returns an error, iteration stops.
 type RecordCallback func(off int64, hdr, rec []byte) error
 
+// A TruncatedError reports a torn record at the end of a log, such as a
+// record which is still being written, or which was left incomplete by a
+// crash during AppendRecordToFile. All records before Offset are valid.
+type TruncatedError struct {
+	Offset int64 // offset of the torn record
+	Err    error
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("%v at offset %v", e.Err, e.Offset)
+}
+
 // ForeachFileRecord calls fn for each record in the named file.
 // Calls to fn are made serially.
 // If fn returns an error, iteration ends and that error is returned.
@@ -67,6 +103,8 @@ func ForeachFileRecord(path string, fn RecordCallback) error {
 // Calls to fn are made serially.
 // If fn returns an error, iteration ends and that error is returned.
 // The startOffset be 0 if reading from the beginning of a file.
+//
+// A torn last record is reported as *TruncatedError.
 func ForeachRecord(r io.Reader, startOffset int64, fn RecordCallback) error {
 	off := startOffset
 	br := bufio.NewReader(r)
@@ -79,49 +117,99 @@ func ForeachRecord(r io.Reader, startOffset int64, fn RecordCallback) error {
 			if err == io.EOF && len(hdr) == 0 {
 				return nil
 			}
+			if err == io.EOF {
+				return &TruncatedError{Offset: startOff, Err: errTornHeader}
+			}
 			return err
 		}
-		if len(hdr) > 40 {
-			return fmt.Errorf("malformed overlong header %q at offset %v", hdr[:40], startOff)
+		if len(hdr) > maxHeaderLen {
+			return fmt.Errorf("malformed overlong header %q at offset %v", hdr[:maxHeaderLen], startOff)
 		}
 		hdrBuf.Reset()
 		hdrBuf.Write(hdr)
-		if !bytes.HasPrefix(hdr, headerPrefix) || !bytes.HasSuffix(hdr, headerSuffix) || bytes.Count(hdr, plus) != 1 {
-			return fmt.Errorf("malformed header %q at offset %v", hdr, startOff)
-		}
-		plusPos := bytes.IndexByte(hdr, '+')
-		hdrOff, err := strconv.ParseInt(string(hdr[len(headerPrefix):plusPos]), 16, 64)
+		hdrOff, hdrSize, sum, hasSum, err := parseHeader(hdr)
 		if err != nil {
-			return fmt.Errorf("malformed header %q (malformed offset) at offset %v", hdr, startOff)
+			return fmt.Errorf("malformed header %q (%v) at offset %v", hdr, err, startOff)
 		}
 		if hdrOff != startOff {
 			return fmt.Errorf("malformed header %q with offset %v doesn't match expected offset %v", hdr, hdrOff, startOff)
 		}
-		hdrSize, err := strconv.ParseInt(string(hdr[plusPos+1:len(hdr)-1]), 16, 64)
-		if err != nil {
-			return fmt.Errorf("malformed header %q (bad size) at offset %v", hdr, startOff)
-		}
 		off += int64(len(hdr))
 
 		buf.Reset()
 		if _, err := io.CopyN(&buf, br, hdrSize); err != nil {
+			if err == io.EOF {
+				return &TruncatedError{Offset: startOff, Err: errTornRecord}
+			}
 			return fmt.Errorf("truncated record at offset %v: %v", startOff, err)
 		}
 		off += hdrSize
+		if hasSum && crc32.Checksum(buf.Bytes(), castagnoli) != sum {
+			if _, err := br.Peek(1); err == io.EOF {
+				// the data of the last record was not written completely
+				return &TruncatedError{Offset: startOff, Err: errTornChecksum}
+			}
+			return fmt.Errorf("checksum mismatch in record at offset %v", startOff)
+		}
 		if err := fn(startOff, hdrBuf.Bytes(), buf.Bytes()); err != nil {
 			return err
 		}
 	}
 }
 
+// parseHeader parses a record header of either format.
+func parseHeader(hdr []byte) (off, size int64, sum uint32, hasSum bool, err error) {
+	var s []byte
+	switch {
+	case bytes.HasPrefix(hdr, header2Prefix):
+		s, hasSum = hdr[len(header2Prefix):], true
+	case bytes.HasPrefix(hdr, headerPrefix):
+		s = hdr[len(headerPrefix):]
+	default:
+		return 0, 0, 0, false, errors.New("unknown prefix")
+	}
+	s = bytes.TrimSuffix(s, headerSuffix)
+	if bytes.Count(s, plus) != 1 {
+		return 0, 0, 0, false, errors.New("malformed size")
+	}
+	plusPos := bytes.IndexByte(s, '+')
+	if off, err = strconv.ParseInt(string(s[:plusPos]), 16, 64); err != nil {
+		return 0, 0, 0, false, errors.New("malformed offset")
+	}
+	s = s[plusPos+1:]
+	if hasSum {
+		commaPos := bytes.IndexByte(s, ',')
+		if commaPos < 0 || bytes.Count(s, comma) != 1 {
+			return 0, 0, 0, false, errors.New("missing checksum")
+		}
+		sum64, err := strconv.ParseUint(string(s[commaPos+1:]), 16, 32)
+		if err != nil {
+			return 0, 0, 0, false, errors.New("malformed checksum")
+		}
+		sum = uint32(sum64)
+		s = s[:commaPos]
+	}
+	if size, err = strconv.ParseInt(string(s), 16, 64); err != nil || size < 0 {
+		return 0, 0, 0, false, errors.New("bad size")
+	}
+	return off, size, sum, hasSum, nil
+}
+
 // AppendRecordToFile opens the named filename for append (creating it
 // if necessary) and adds the provided data record to the end.
-// The caller is responsible for file locking.
+// Appends of other processes are serialized by LockFile, so their records
+// do not interleave. Readers need no lock, they see a torn last record at
+// worst.
 func AppendRecordToFile(filename string, data []byte) error {
 	f, err := os.OpenFile(filename, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0600)
 	if err != nil {
 		return err
 	}
+	// closing the file releases the lock, also on errors
+	defer f.Close()
+	if err := LockFile(f); err != nil {
+		return err
+	}
 	off, err := f.Seek(0, io.SeekEnd)
 	if err != nil {
 		return err
@@ -134,17 +222,54 @@ func AppendRecordToFile(filename string, data []byte) error {
 		return fmt.Errorf("Size %v != offset %v", st.Size(), off)
 	}
 	if err := WriteRecord(f, off, data); err != nil {
-		f.Close()
 		return err
 	}
 	return f.Close()
 }
 
+// RecoverFile removes a torn record from the end of the named file, so
+// new records can be appended again. It returns the number of removed
+// bytes. Missing files need no recovery. Like AppendRecordToFile, it locks
+// the file, so a record being appended by another process is not mistaken
+// for a torn one.
+func RecoverFile(filename string) (int64, error) {
+	f, err := os.OpenFile(filename, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if err := LockFile(f); err != nil {
+		return 0, err
+	}
+	err = ForeachRecord(f, 0, func(off int64, hdr, rec []byte) error { return nil })
+	te, ok := err.(*TruncatedError)
+	if !ok {
+		if err != nil {
+			return 0, fmt.Errorf("error in %s: %v", filename, err)
+		}
+		return 0, nil
+	}
+	st, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if err := f.Truncate(te.Offset); err != nil {
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, err
+	}
+	return st.Size() - te.Offset, nil
+}
+
 // WriteRecord writes the record data to w, formatting the record
 // wrapper with the given offset off. It is the caller's
 // responsibility to pass the correct offset. Exactly one Write
 // call will be made to w.
 func WriteRecord(w io.Writer, off int64, data []byte) error {
-	_, err := fmt.Fprintf(w, "REC@%x+%x=%s", off, len(data), data)
+	_, err := fmt.Fprintf(w, "REC2@%x+%x,%x=%s", off, len(data), crc32.Checksum(data, castagnoli), data)
 	return err
 }
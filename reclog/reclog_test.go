@@ -0,0 +1,102 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package reclog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func records(t *testing.T, log string) ([]string, error) {
+	var recs []string
+	err := ForeachRecord(strings.NewReader(log), 0, func(off int64, hdr, rec []byte) error {
+		recs = append(recs, string(rec))
+		return nil
+	})
+	return recs, err
+}
+
+func TestForeachRecord(t *testing.T) {
+	var buf bytes.Buffer
+	WriteRecord(&buf, 0, []byte("foo"))
+	WriteRecord(&buf, int64(buf.Len()), []byte("bar"))
+	log := buf.String()
+	mixed := bytes.NewBufferString("REC@0+3=foo")
+	WriteRecord(mixed, int64(mixed.Len()), []byte("bar"))
+	if !strings.HasPrefix(log, "REC2@0+3,") {
+		t.Fatalf("unexpected header in %q", log)
+	}
+
+	for _, tt := range []struct {
+		log       string
+		recs      int
+		truncated bool
+		err       bool
+	}{
+		{log: "", recs: 0},
+		{log: log, recs: 2},
+		{log: "REC@0+3=fooREC@b+3=bar", recs: 2},                         // old format
+		{log: mixed.String(), recs: 2},                                   // mixed formats
+		{log: log[:len(log)-1], recs: 1, truncated: true},                // torn data
+		{log: log[:len(log)-5], recs: 1, truncated: true},                // torn header
+		{log: log[:len(log)-1] + "x", recs: 1, truncated: true},          // checksum mismatch of last record
+		{log: log + "\x00\x00\x00", recs: 2, truncated: true},            // zeroed tail
+		{log: strings.Replace(log, "foo", "fox", 1), recs: 0, err: true}, // checksum mismatch
+		{log: "REC2@0+3=foo", recs: 0, err: true},                        // missing checksum
+	} {
+		recs, err := records(t, tt.log)
+		_, truncated := err.(*TruncatedError)
+		if len(recs) != tt.recs || truncated != tt.truncated || (err != nil && !truncated) != tt.err {
+			t.Errorf("%q: expected %d records (truncated %v, error %v), got %d: %v", tt.log, tt.recs, tt.truncated, tt.err, len(recs), err)
+		}
+	}
+}
+
+func TestRecoverFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "reclog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "test.mutlog")
+
+	if n, err := RecoverFile(filename); n != 0 || err != nil {
+		t.Errorf("missing files need no recovery, got %d, %v", n, err)
+	}
+	for _, rec := range []string{"foo", "bar"} {
+		if err := AppendRecordToFile(filename, []byte(rec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	valid, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := RecoverFile(filename); n != 0 || err != nil {
+		t.Errorf("valid files need no recovery, got %d, %v", n, err)
+	}
+
+	if err := ioutil.WriteFile(filename, append(valid, "REC2@1a+3"...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := RecoverFile(filename); n != 9 || err != nil {
+		t.Errorf("expected the torn record of 9 bytes to be removed, got %d, %v", n, err)
+	}
+	if err := AppendRecordToFile(filename, []byte("baz")); err != nil {
+		t.Fatal(err)
+	}
+	var recs []string
+	err = ForeachFileRecord(filename, func(off int64, hdr, rec []byte) error {
+		recs = append(recs, string(rec))
+		return nil
+	})
+	if err != nil || strings.Join(recs, ",") != "foo,bar,baz" {
+		t.Errorf("expected records foo,bar,baz, got %v, %v", recs, err)
+	}
+}
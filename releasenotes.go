@@ -0,0 +1,119 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ReleaseNotes lists the closed issues of a release grouped by project and
+// section.
+type ReleaseNotes struct {
+	Release  *Release
+	Projects []*ReleaseNotesProject
+}
+
+// ReleaseNotesProject lists the closed issues of a project in a release.
+type ReleaseNotesProject struct {
+	Project  *Project
+	Sections []*ReleaseNotesSection
+}
+
+// ReleaseNotesSection lists the closed issues of a project with the same
+// issue type or label.
+type ReleaseNotesSection struct {
+	Title  string
+	Issues []*Issue
+}
+
+// Release notes group issues by:
+const (
+	GroupByType  = "type"
+	GroupByLabel = "label"
+)
+
+// otherSection is the section of issues without type or label.
+const otherSection = "Other"
+
+// Notes returns the release notes of the release built from the closed
+// issues of its milestones. Issues are grouped by GroupByType or
+// GroupByLabel. Issues with multiple labels are listed in the section of
+// their first label.
+func (r *Release) Notes(groupBy string) *ReleaseNotes {
+	projects := make(map[*Project]map[string]*ReleaseNotesSection)
+	seen := newSet()
+	for _, m := range r.Milestones {
+		for _, i := range m.Issues {
+			if !i.Closed || seen.has(i.ID) {
+				continue
+			}
+			seen.put(i.ID)
+			sections, ok := projects[i.p]
+			if !ok {
+				sections = make(map[string]*ReleaseNotesSection)
+				projects[i.p] = sections
+			}
+			title := i.notesSection(groupBy)
+			s, ok := sections[title]
+			if !ok {
+				s = &ReleaseNotesSection{Title: title}
+				sections[title] = s
+			}
+			s.Issues = append(s.Issues, i)
+		}
+	}
+
+	notes := &ReleaseNotes{Release: r}
+	for p, sections := range projects {
+		np := &ReleaseNotesProject{Project: p}
+		for _, s := range sections {
+			sort.Slice(s.Issues, func(i, j int) bool { return s.Issues[i].IssueKey < s.Issues[j].IssueKey })
+			np.Sections = append(np.Sections, s)
+		}
+		sort.Slice(np.Sections, func(i, j int) bool {
+			a, b := np.Sections[i].Title, np.Sections[j].Title
+			if (a == otherSection) != (b == otherSection) {
+				return b == otherSection
+			}
+			return a < b
+		})
+		notes.Projects = append(notes.Projects, np)
+	}
+	sort.Slice(notes.Projects, func(i, j int) bool {
+		return notes.Projects[i].Name() < notes.Projects[j].Name()
+	})
+	return notes
+}
+
+func (i *Issue) notesSection(groupBy string) string {
+	var title string
+	switch groupBy {
+	case GroupByLabel:
+		if labels := i.LabelList(); len(labels) > 0 {
+			title = labels[0].Name
+		}
+	default:
+		title = i.Type
+	}
+	if title == "" {
+		return otherSection
+	}
+	r, n := utf8.DecodeRuneInString(title)
+	return string(unicode.ToUpper(r)) + title[n:]
+}
+
+// Name returns the name of the project, or its ID if the project has no
+// name.
+func (np *ReleaseNotesProject) Name() string {
+	if np.Project == nil {
+		return ""
+	}
+	if np.Project.Name != "" {
+		return np.Project.Name
+	}
+	return np.Project.ID
+}
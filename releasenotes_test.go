@@ -0,0 +1,65 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"testing"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Project: &devdashpb.ProjectMutation{
+			Id:         "ABC",
+			Name:       "Alpha Bravo Charlie",
+			Milestones: []*devdashpb.TrackerMilestone{{Id: "m1", Project: "ABC", Name: "1.0.0"}},
+		},
+	}))
+	for _, im := range []*devdashpb.IssueMutation{
+		{Id: "i1", IssueKey: "ABC-1", Type: "bug", Closed: pbBool(true)},
+		{Id: "i2", IssueKey: "ABC-2", Type: "story", Closed: pbBool(true),
+			Labels: []*devdashpb.TrackerLabel{{Name: "enhancement"}}},
+		{Id: "i3", IssueKey: "ABC-3", Type: "bug"},
+		{Id: "i4", IssueKey: "ABC-4", Closed: pbBool(true)},
+	} {
+		im.Project = "ABC"
+		im.Milestones = []*devdashpb.TrackerMilestone{{Id: "m1"}}
+		checkErr(t, l.Log(&devdashpb.Mutation{Issue: im}))
+	}
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Release: &devdashpb.ReleaseMutation{
+			Id:         "r1",
+			Name:       "Release 2019.02",
+			Milestones: []*devdashpb.TrackerMilestone{{Id: "m1"}},
+		},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	notes := c.Releases["r1"].Notes(GroupByType)
+	if len(notes.Projects) != 1 || notes.Projects[0].Name() != "Alpha Bravo Charlie" {
+		t.Fatalf("unexpected projects %v", notes.Projects)
+	}
+	var titles []string
+	for _, s := range notes.Projects[0].Sections {
+		titles = append(titles, s.Title)
+		if len(s.Issues) != 1 {
+			t.Errorf("section %s should have 1 issue, got %d", s.Title, len(s.Issues))
+		}
+	}
+	if len(titles) != 3 || titles[0] != "Bug" || titles[1] != "Story" || titles[2] != "Other" {
+		t.Errorf("unexpected sections %v", titles)
+	}
+
+	notes = c.Releases["r1"].Notes(GroupByLabel)
+	if s := notes.Projects[0].Sections; len(s) != 2 || s[0].Title != "Enhancement" || len(s[1].Issues) != 2 {
+		t.Errorf("unexpected sections grouped by label %v", s)
+	}
+}
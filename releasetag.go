@@ -0,0 +1,164 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReleaseTag is a git tag matching the tag pattern of a release.
+type ReleaseTag struct {
+	Ref    GitRef
+	Commit *GitCommit
+}
+
+// Repo returns the repository of the tag.
+func (t *ReleaseTag) Repo() *GitRepo {
+	return t.Ref.r
+}
+
+// Date returns the commit time of the tagged commit.
+func (t *ReleaseTag) Date() time.Time {
+	return t.Commit.CommitTime
+}
+
+// SetReleaseTagPattern sets the glob pattern matching the tags of releases
+// without TagPattern. "{name}" is replaced by the name of the release.
+// Releases without TagPattern have no tags if pattern is empty.
+func (c *Corpus) SetReleaseTagPattern(pattern string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.releaseTagPattern = pattern
+	for _, r := range c.Releases {
+		c.indexTags(r)
+	}
+}
+
+// tagPattern returns the glob pattern matching the tags of the release.
+func (r *Release) tagPattern() string {
+	if r.TagPattern != "" {
+		return r.TagPattern
+	}
+	if r.Name == "" || r.c == nil || r.c.releaseTagPattern == "" {
+		return ""
+	}
+	return strings.Replace(r.c.releaseTagPattern, "{name}", globEscaper.Replace(r.Name), -1)
+}
+
+var globEscaper = strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`, `\`, `\\`)
+
+// indexTags finds the tags of the release, if its tag pattern changed.
+func (c *Corpus) indexTags(r *Release) {
+	pattern := r.tagPattern()
+	if pattern == r.tags.pattern {
+		return
+	}
+	r.tags.pattern = pattern
+	r.tags.refs = nil
+	if pattern == "" {
+		return
+	}
+	for _, repo := range c.GitRepos {
+		for _, ref := range repo.refs {
+			if ok, _ := path.Match(pattern, ref.Name()); ok && ref.IsTag() {
+				r.tags.refs = append(r.tags.refs, ref)
+			}
+		}
+	}
+}
+
+// updateTag updates the tags of all releases after the named tag of repo
+// was set to sha1, or deleted if sha1 is empty.
+func (c *Corpus) updateTag(repo *GitRepo, name, sha1 string) {
+	ref := GitRef{r: repo, Ref: name, Sha1: sha1}
+	for _, r := range c.Releases {
+		refs := r.tags.refs[:0]
+		for _, t := range r.tags.refs {
+			if t.r != repo || t.Ref != name {
+				refs = append(refs, t)
+			}
+		}
+		if sha1 != "" && r.tags.pattern != "" {
+			if ok, _ := path.Match(r.tags.pattern, ref.Name()); ok {
+				refs = append(refs, ref)
+			}
+		}
+		r.tags.refs = refs
+	}
+}
+
+// Tags returns all tags of all repositories matching the tag pattern of the
+// release, sorted by date. Tags pointing to unknown commits are ignored.
+func (r *Release) Tags() []*ReleaseTag {
+	var ret []*ReleaseTag
+	for _, ref := range r.tags.refs {
+		if gc := ref.Commit(); gc != nil {
+			ret = append(ret, &ReleaseTag{Ref: ref, Commit: gc})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].before(ret[j]) })
+	return ret
+}
+
+func (t *ReleaseTag) before(u *ReleaseTag) bool {
+	if !t.Date().Equal(u.Date()) {
+		return t.Date().Before(u.Date())
+	}
+	return t.Ref.Ref < u.Ref.Ref
+}
+
+// Tag returns the earliest tag of the release, or nil if the release is not
+// tagged yet.
+func (r *Release) Tag() *ReleaseTag {
+	var first *ReleaseTag
+	for _, ref := range r.tags.refs {
+		gc := ref.Commit()
+		if gc == nil {
+			continue
+		}
+		if t := (&ReleaseTag{Ref: ref, Commit: gc}); first == nil || t.before(first) {
+			first = t
+		}
+	}
+	return first
+}
+
+// ActualReleaseDate returns the date of the earliest tag of the release, or
+// the zero time if the release is not tagged yet.
+func (r *Release) ActualReleaseDate() time.Time {
+	if t := r.Tag(); t != nil {
+		return t.Date()
+	}
+	return time.Time{}
+}
+
+// Delay returns how much later than planned the release was tagged. Releases
+// which are not tagged yet are delayed by the time passed since the planned
+// release date. Releases without tag pattern or planned date have no delay.
+func (r *Release) Delay() time.Duration {
+	if r.tagPattern() == "" || r.ReleaseDate.IsZero() {
+		return 0
+	}
+	actual := r.ActualReleaseDate()
+	if actual.IsZero() {
+		if r.Closed {
+			return 0
+		}
+		actual = time.Now()
+	}
+	if d := actual.Sub(r.ReleaseDate); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// IsLate reports whether the release was tagged after its planned release
+// date, or is not tagged yet although the planned release date has passed.
+func (r *Release) IsLate() bool {
+	return r.Delay() > 0
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestReleaseTag(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	// commits are authored on 2019-01-01 and 2019-01-03:
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{Repo: "https://example.com/abc.git", Commit: testCommit("aaaa1", "", "1.0", 0)},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo:   "https://example.com/abc.git",
+			Commit: testCommit("bbbb2", "aaaa1", "1.0.1", 2*24*3600),
+			Refs: []*devdashpb.GitRef{
+				{Ref: "refs/tags/v1.0.0", Sha1: "aaaa1"},
+				{Ref: "refs/tags/v1.0.1", Sha1: "bbbb2"},
+				{Ref: "refs/heads/v1.1.0", Sha1: "bbbb2"},
+			},
+		},
+	}))
+	for _, rm := range []*devdashpb.ReleaseMutation{
+		{Id: "r1", Name: "1.0", TagPattern: "v1.0.*",
+			ReleaseDate: pbTimestamp(time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC))},
+		{Id: "r2", Name: "1.1", TagPattern: "v1.1.*",
+			ReleaseDate: pbTimestamp(time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC))},
+		{Id: "r3", Name: "1.2",
+			ReleaseDate: pbTimestamp(time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC))},
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Release: rm}))
+	}
+	// tags are found for releases created earlier:
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo: "https://example.com/abc.git",
+			Refs: []*devdashpb.GitRef{{Ref: "refs/tags/v1.2.0", Sha1: "bbbb2"}},
+		},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	r1 := c.Releases["r1"]
+	if tags := r1.Tags(); len(tags) != 2 || tags[0].Ref.Name() != "v1.0.0" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if !r1.ActualReleaseDate().Equal(time.Unix(1546300800, 0)) || r1.IsLate() {
+		t.Errorf("Release r1 should have been tagged on time, got %v", r1.ActualReleaseDate())
+	}
+
+	r2 := c.Releases["r2"]
+	if r2.Tag() != nil {
+		t.Error("branches should not match the tag pattern")
+	}
+	if !r2.IsLate() {
+		t.Error("Release r2 should be late")
+	}
+
+	r3 := c.Releases["r3"]
+	if r3.Tag() != nil || r3.IsLate() {
+		t.Error("releases without tag pattern should not be late")
+	}
+	c.SetReleaseTagPattern("v{name}.*")
+	if tag := r3.Tag(); tag == nil || tag.Ref.Name() != "v1.2.0" || !r3.IsLate() {
+		t.Errorf("release r3 should be tagged late by the release tag pattern, got %v", tag)
+	}
+	c.processGitMutation(&devdashpb.GitMutation{Repo: "https://example.com/abc.git", DeletedRefs: []string{"refs/tags/v1.2.0"}})
+	if r3.Tag() != nil {
+		t.Errorf("deleted tag v1.2.0 should be removed from release r3, got %v", r3.Tag())
+	}
+	c.processGitMutation(&devdashpb.GitMutation{
+		Repo: "https://example.com/abc.git",
+		Refs: []*devdashpb.GitRef{{Ref: "refs/tags/v1.20.0", Sha1: "bbbb2"}, {Ref: "refs/tags/v1.2.1", Sha1: "bbbb2"}},
+	})
+	if tags := r3.Tags(); len(tags) != 1 || tags[0].Ref.Name() != "v1.2.1" {
+		t.Errorf("expected tag v1.2.1 of release r3, got %v", tags)
+	}
+}
+
+func TestReleaseTagPattern(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Release: &devdashpb.ReleaseMutation{Id: "r1", Name: "2019.01 [beta]"},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Git: &devdashpb.GitMutation{
+			Repo:   "https://example.com/abc.git",
+			Commit: testCommit("aaaa1", "", "release", 0),
+			Refs: []*devdashpb.GitRef{
+				{Ref: "refs/tags/v2019.01 [beta]", Sha1: "aaaa1"},
+				{Ref: "refs/tags/release-2019.01 b", Sha1: "aaaa1"},
+				{Ref: "refs/tags/release-2019.01 [beta]", Sha1: "aaaa1"},
+			},
+		},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	r1 := c.Releases["r1"]
+	if tag := r1.Tag(); tag != nil {
+		t.Errorf("releases without tag pattern should not be tagged, got %v", tag)
+	}
+	c.SetReleaseTagPattern("v{name}")
+	if tag := r1.Tag(); tag == nil || tag.Ref.Name() != "v2019.01 [beta]" {
+		t.Errorf("expected tag v2019.01 [beta], got %v", tag)
+	}
+	c.SetReleaseTagPattern("release-{name}")
+	if tags := r1.Tags(); len(tags) != 1 || tags[0].Ref.Name() != "release-2019.01 [beta]" {
+		t.Errorf("expected tag release-2019.01 [beta], got %v", tags)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urld/devdashboard/reclog"
+)
+
+// Rotation configures when a DiskMutationLogger starts a new log file.
+// The zero value starts a new file each UTC day.
+type Rotation struct {
+	Period     time.Duration // start a new file each period, aligned to UTC; a day if zero
+	MaxSize    int64         // start a new file once the current one has this many bytes, if > 0
+	MaxRecords int           // start a new file once the current one has this many records, if > 0
+}
+
+// filename returns the path of the file to append the next record to.
+//
+// Log files are named after the period they were started in, such as
+// "devdashboard-2019-01-02.mutlog" for daily files, or
+// "devdashboard-2019-01-02T150000.mutlog" for shorter periods. Files
+// started within a period, because the previous file is full, are numbered,
+// such as "devdashboard-2019-01-02_000001.mutlog". A new file always comes
+// after all existing ones in lexical order, which ForeachFile relies on,
+// even if the rotation policy changed. d.mu must be held.
+func (d *DiskMutationLogger) filename() string {
+	if d.current == "" {
+		d.current = d.newestFile()
+		if d.current != "" {
+			d.records = countRecords(d.current)
+			d.period = segmentBase(d.current)
+		}
+	}
+	period := d.Rotation.periodName(time.Now())
+	newPeriod := d.current == "" || d.period < period
+	if !newPeriod && !d.full() {
+		return d.current
+	}
+	next := d.path(period + ".mutlog")
+	if d.current != "" && (!newPeriod || next <= d.current) {
+		next = d.path(nextSequence(d.current))
+	}
+	if newPeriod {
+		d.period = period
+	}
+	d.sealed = d.current
+	d.sealedRecords = d.records
+	d.current, d.records = next, 0
+	return d.current
+}
+
+// periodName returns the name of the log files of the period t is in,
+// without extension.
+func (r Rotation) periodName(t time.Time) string {
+	period := r.Period
+	if period <= 0 {
+		period = 24 * time.Hour
+	}
+	start := t.UTC().Truncate(period)
+	if period%(24*time.Hour) == 0 {
+		return "devdashboard-" + start.Format("2006-01-02")
+	}
+	return "devdashboard-" + start.Format("2006-01-02T150405")
+}
+
+// full reports whether a new file must be started, because the current
+// one reached the maximum size or record count, or is compressed.
+func (d *DiskMutationLogger) full() bool {
+	if strings.HasSuffix(d.current, compressedSuffix) {
+		return true
+	}
+	if d.Rotation.MaxRecords > 0 && d.records >= d.Rotation.MaxRecords {
+		return true
+	}
+	if d.Rotation.MaxSize > 0 {
+		if fi, err := os.Stat(d.current); err == nil && fi.Size() >= d.Rotation.MaxSize {
+			return true
+		}
+	}
+	return false
+}
+
+// newestFile returns the path of the newest log file, or "" if there is
+// none. d.mu must be held.
+func (d *DiskMutationLogger) newestFile() string {
+	var newest string
+	d.foreachFile(func(fullPath string, fi os.FileInfo) error {
+		newest = fullPath
+		return nil
+	})
+	return newest
+}
+
+// segmentBase returns the name of the period of a log file, such as
+// "devdashboard-2019-01-02" for "devdashboard-2019-01-02_000001.mutlog".
+func segmentBase(path string) string {
+	name := strings.TrimSuffix(filepath.Base(segmentPath(path)), ".mutlog")
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// nextSequence returns the name of the log file following the one at
+// path within the same period.
+func nextSequence(path string) string {
+	name := strings.TrimSuffix(filepath.Base(segmentPath(path)), ".mutlog")
+	seq := 0
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		seq, _ = strconv.Atoi(name[i+1:])
+		name = name[:i]
+	}
+	return fmt.Sprintf("%s_%06d.mutlog", name, seq+1)
+}
+
+// countRecords returns the number of valid records in the named file.
+func countRecords(path string) int {
+	n := 0
+	reclog.ForeachFileRecord(path, func(off int64, hdr, rec []byte) error {
+		n++
+		return nil
+	})
+	return n
+}
+
+// ManifestFile is the name of the manifest in the log directory. It is
+// rewritten whenever a log file is started or compressed.
+const ManifestFile = "devdashboard-manifest.json"
+
+// A Manifest lists the log files of a DiskMutationLogger in the order they
+// were written. It is informational: the log files in the directory are
+// authoritative.
+type Manifest struct {
+	Updated  time.Time         `json:"updated"`
+	Segments []ManifestSegment `json:"segments"`
+}
+
+// ManifestSegment is a log file listed in a Manifest.
+type ManifestSegment struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`              // of the file, which may be compressed
+	Records    int    `json:"records,omitempty"` // zero if unknown
+	Compressed bool   `json:"compressed,omitempty"`
+	Sealed     bool   `json:"sealed"` // no more records are appended
+}
+
+// ReadManifest reads the manifest of the log directory.
+func ReadManifest(directory string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(directory, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m := new(Manifest)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("%s: %v", ManifestFile, err)
+	}
+	return m, nil
+}
+
+// writeManifest rewrites the manifest. Record counts are taken from the
+// previous manifest, unless known by the logger. d.mu must be held.
+func (d *DiskMutationLogger) writeManifest() error {
+	records := make(map[string]int)
+	if old, err := ReadManifest(d.directory); err == nil {
+		for _, seg := range old.Segments {
+			records[segmentPath(seg.Name)] = seg.Records
+		}
+	}
+	if d.sealed != "" {
+		records[filepath.Base(segmentPath(d.sealed))] = d.sealedRecords
+	}
+	current := d.current
+	if current == "" {
+		current = d.newestFile()
+	} else {
+		records[filepath.Base(segmentPath(current))] = d.records
+	}
+
+	m := &Manifest{Updated: time.Now().UTC(), Segments: []ManifestSegment{}}
+	err := d.foreachFile(func(fullPath string, fi os.FileInfo) error {
+		m.Segments = append(m.Segments, ManifestSegment{
+			Name:       fi.Name(),
+			Size:       fi.Size(),
+			Records:    records[segmentPath(fi.Name())],
+			Compressed: segmentPath(fullPath) != fullPath,
+			Sealed:     fullPath != current,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(d.directory, "tmp-manifest-")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), d.path(ManifestFile))
+}
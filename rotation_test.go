@@ -0,0 +1,94 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestRotationNames(t *testing.T) {
+	now := time.Date(2019, 1, 2, 15, 4, 5, 0, time.FixedZone("CET", 3600))
+	for _, tt := range []struct {
+		period time.Duration
+		want   string
+	}{
+		{0, "devdashboard-2019-01-02"},
+		{time.Hour, "devdashboard-2019-01-02T140000"},
+		{15 * time.Minute, "devdashboard-2019-01-02T140000"},
+	} {
+		if got := (Rotation{Period: tt.period}).periodName(now); got != tt.want {
+			t.Errorf("periodName with period %v: expected %s, got %s", tt.period, tt.want, got)
+		}
+	}
+	for _, tt := range []struct{ path, next, base string }{
+		{"/tmp/devdashboard-2019-01-02.mutlog", "devdashboard-2019-01-02_000001.mutlog", "devdashboard-2019-01-02"},
+		{"/tmp/devdashboard-2019-01-02_000009.mutlog.gz", "devdashboard-2019-01-02_000010.mutlog", "devdashboard-2019-01-02"},
+	} {
+		if next := nextSequence(tt.path); next != tt.next {
+			t.Errorf("nextSequence(%s): expected %s, got %s", tt.path, tt.next, next)
+		}
+		if base := segmentBase(tt.path); base != tt.base {
+			t.Errorf("segmentBase(%s): expected %s, got %s", tt.path, tt.base, base)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	// the files of each logger, including one with a different policy,
+	// must be in lexical order:
+	var written []string
+	for _, r := range []Rotation{
+		{MaxRecords: 2},
+		{Period: time.Minute, MaxRecords: 2},
+		{MaxSize: 1},
+	} {
+		d := NewDiskMutationLogger(dir)
+		d.Rotation = r
+		for i := 0; i < 3; i++ {
+			checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{Id: "ABC"}}))
+			if n := len(written); n == 0 || written[n-1] != filepath.Base(d.current) {
+				written = append(written, filepath.Base(d.current))
+			}
+		}
+		checkErr(t, d.Release())
+	}
+	var files []string
+	checkErr(t, NewDiskMutationLogger(dir).ForeachFile(func(fullPath string, fi os.FileInfo) error {
+		files = append(files, fi.Name())
+		return nil
+	}))
+	if len(files) != len(written) {
+		t.Fatalf("expected files %v, got %v", written, files)
+	}
+	for i := range files {
+		if files[i] != written[i] {
+			t.Fatalf("expected files %v in this order, got %v", written, files)
+		}
+	}
+	if len(files) != 7 {
+		t.Errorf("expected 2+2+3 files, got %v", files)
+	}
+
+	m, err := ReadManifest(dir)
+	checkErr(t, err)
+	if len(m.Segments) != len(files) {
+		t.Fatalf("expected %d segments in manifest, got %+v", len(files), m.Segments)
+	}
+	if seg := m.Segments[0]; seg.Name != files[0] || seg.Records != 2 || !seg.Sealed {
+		t.Errorf("unexpected first segment %+v", seg)
+	}
+	if seg := m.Segments[len(files)-1]; seg.Records != 1 || seg.Sealed {
+		t.Errorf("unexpected last segment %+v", seg)
+	}
+}
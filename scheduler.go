@@ -0,0 +1,196 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SyncStatus is the state of a syncer run by a Scheduler.
+type SyncStatus struct {
+	Name     string
+	Interval time.Duration
+
+	Running     bool
+	LastRun     time.Time     // start of the last sync
+	Duration    time.Duration // duration of the last sync
+	LastSuccess time.Time     // end of the last successful sync
+	LastError   string
+	LastErrorAt time.Time
+	Failures    int // number of consecutive failed syncs
+	NextRun     time.Time
+}
+
+// Scheduler runs syncers periodically. The time between two syncs is
+// randomly extended by up to the jitter of the syncer, so syncers of the
+// same interval don't hit their sources at the same time. Failed syncs
+// are retried with exponential backoff.
+type Scheduler struct {
+	Corpus *Corpus        // passed to the syncers, may be nil
+	Logger MutationLogger // logs the mutations of all syncers
+
+	// Synced, if not nil, is called after each successful sync, such as
+	// to update the corpus from the logged mutations. Calls are made
+	// serially.
+	Synced func(ctx context.Context) error
+
+	syncedMu sync.Mutex // serializes calls to Synced
+
+	mu   sync.Mutex // guards jobs and their status
+	jobs []*syncJob
+}
+
+type syncJob struct {
+	syncer     Syncer
+	interval   time.Duration
+	jitter     time.Duration
+	maxBackoff time.Duration
+
+	status SyncStatus
+}
+
+// minBackoff is the delay after the first failed sync, unless the interval
+// of the syncer is shorter.
+const minBackoff = 10 * time.Second
+
+// Add adds a syncer which is run every interval. After failures, the
+// delay grows up to maxBackoff, or interval if maxBackoff is zero.
+// Add must not be called after Run.
+func (s *Scheduler) Add(sy Syncer, interval, jitter, maxBackoff time.Duration) {
+	if interval <= 0 {
+		panic("non-positive sync interval")
+	}
+	if maxBackoff < interval {
+		maxBackoff = interval
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &syncJob{
+		syncer:     sy,
+		interval:   interval,
+		jitter:     jitter,
+		maxBackoff: maxBackoff,
+		status:     SyncStatus{Name: sy.Name(), Interval: interval},
+	})
+}
+
+// Status returns the status of all syncers sorted by name.
+func (s *Scheduler) Status() []SyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ret := make([]SyncStatus, len(s.jobs))
+	for i, j := range s.jobs {
+		ret[i] = j.status
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// SyncAll runs all syncers once, one after another. It returns the first
+// error, after all syncers ran.
+func (s *Scheduler) SyncAll(ctx context.Context) error {
+	var first error
+	for _, j := range s.jobs {
+		if err := s.run(ctx, j); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Run runs all syncers immediately and then periodically, until the
+// context is done.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j *syncJob) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *syncJob) {
+	for {
+		s.run(ctx, j)
+		s.mu.Lock()
+		delay := j.delay()
+		j.status.NextRun = time.Now().Add(delay)
+		s.mu.Unlock()
+
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// run runs the syncer once and records its status.
+func (s *Scheduler) run(ctx context.Context, j *syncJob) error {
+	start := time.Now()
+	s.mu.Lock()
+	j.status.Running = true
+	j.status.LastRun = start
+	s.mu.Unlock()
+
+	err := j.syncer.Sync(ctx, s.Corpus, s.Logger)
+	if err == nil && s.Synced != nil {
+		s.syncedMu.Lock()
+		err = s.Synced(ctx)
+		s.syncedMu.Unlock()
+		if err != nil {
+			err = fmt.Errorf("update after sync: %v", err)
+		}
+	}
+
+	end := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.status.Running = false
+	j.status.Duration = end.Sub(start)
+	if err != nil {
+		log.Printf("sync of %s failed: %v", j.status.Name, err)
+		j.status.LastError = err.Error()
+		j.status.LastErrorAt = end
+		j.status.Failures++
+		return err
+	}
+	j.status.LastSuccess = end
+	j.status.Failures = 0
+	return nil
+}
+
+// delay returns the time until the next sync. s.mu must be held.
+func (j *syncJob) delay() time.Duration {
+	d := j.interval
+	if n := j.status.Failures; n > 0 {
+		d = minBackoff
+		if j.interval < d {
+			d = j.interval
+		}
+		for i := 1; i < n && d < j.maxBackoff; i++ {
+			d *= 2
+		}
+		if d > j.maxBackoff {
+			d = j.maxBackoff
+		}
+	}
+	if j.jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(j.jitter)))
+	}
+	return d
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// issueSyncer logs one issue per sync, or fails if err is set.
+type issueSyncer struct {
+	n   int
+	err error
+}
+
+func (s *issueSyncer) Name() string { return "issues" }
+
+func (s *issueSyncer) Sync(ctx context.Context, c *Corpus, l MutationLogger) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.n++
+	key := fmt.Sprintf("ABC-%d", s.n)
+	return l.Log(&devdashpb.Mutation{Issue: &devdashpb.IssueMutation{Id: key, Project: "ABC", IssueKey: key}})
+}
+
+func TestScheduler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	logger := NewDiskMutationLogger(dir)
+	c := &Corpus{}
+	checkErr(t, c.Initialize(context.Background(), logger))
+
+	sy := &issueSyncer{}
+	s := &Scheduler{Corpus: c, Logger: logger, Synced: c.Update}
+	s.Add(sy, time.Minute, 0, time.Hour)
+
+	for n := 1; n <= 2; n++ {
+		checkErr(t, s.SyncAll(context.Background()))
+		if len(c.Issues) != n {
+			t.Fatalf("corpus should have %d issues after sync %d, got %d", n, n, len(c.Issues))
+		}
+	}
+	if logger.offsets == nil || len(logger.offsets) != 1 {
+		t.Errorf("logger should remember the offset of 1 file, got %v", logger.offsets)
+	}
+
+	sy.err = errors.New("tracker unavailable")
+	for n := 1; n <= 3; n++ {
+		if err := s.SyncAll(context.Background()); err != sy.err {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	status := s.Status()[0]
+	if status.Failures != 3 || status.LastError != sy.err.Error() || status.LastSuccess.IsZero() {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if d := s.jobs[0].delay(); d != 4*minBackoff {
+		t.Errorf("delay after 3 failures should be %v, got %v", 4*minBackoff, d)
+	}
+	s.jobs[0].status.Failures = 20
+	if d := s.jobs[0].delay(); d != time.Hour {
+		t.Errorf("delay should be limited to %v, got %v", time.Hour, d)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"sort"
+	"strings"
+)
+
+// Query is a parsed issue search query.
+//
+// A query consists of space separated terms. Terms of the form "field:value"
+// filter issues by a field, all other terms must be contained in the issue
+// key, title or body. Supported fields are project, milestone, label,
+// priority, type, status, assignee and is (open, closed or blocked). Unknown
+// fields filter by custom fields. The term "sort:value" orders the result by
+// key (default), priority, updated, created or points.
+type Query struct {
+	Terms   []string
+	Filters map[string]string
+	Sort    string
+}
+
+// ParseQuery parses an issue search query.
+func ParseQuery(s string) Query {
+	q := Query{Filters: make(map[string]string)}
+	for _, term := range strings.Fields(s) {
+		i := strings.IndexByte(term, ':')
+		if i <= 0 || i == len(term)-1 {
+			q.Terms = append(q.Terms, strings.ToLower(term))
+			continue
+		}
+		field, value := strings.ToLower(term[:i]), term[i+1:]
+		if field == "sort" {
+			q.Sort = strings.ToLower(value)
+			continue
+		}
+		q.Filters[field] = value
+	}
+	return q
+}
+
+// Match reports whether the issue matches all terms and filters of the
+// query.
+func (q Query) Match(i *Issue) bool {
+	text := strings.ToLower(i.IssueKey + "\n" + i.Title + "\n" + i.Body)
+	for _, term := range q.Terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	for field, value := range q.Filters {
+		if !matchField(i, field, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchField(i *Issue, field, value string) bool {
+	eq := strings.EqualFold
+	switch field {
+	case "project":
+		return i.p != nil && (eq(i.p.ID, value) || eq(i.p.Name, value))
+	case "milestone":
+		for _, m := range i.Milestones {
+			if eq(m.ID, value) || eq(m.Name, value) {
+				return true
+			}
+		}
+		return false
+	case "label":
+		for l := range i.Labels {
+			if eq(l, value) {
+				return true
+			}
+		}
+		return false
+	case "priority":
+		return eq(i.Priority, value)
+	case "type":
+		return eq(i.Type, value)
+	case "status":
+		return eq(i.Status, value)
+	case "assignee":
+		for _, u := range i.Assignees {
+			if eq(u.ID, value) || eq(u.Name, value) {
+				return true
+			}
+		}
+		return false
+	case "is":
+		switch strings.ToLower(value) {
+		case "open":
+			return !i.Closed
+		case "closed":
+			return i.Closed
+		case "blocked":
+			return len(i.OpenBlockers()) > 0
+		}
+		return false
+	}
+	f, ok := i.CustomFields[field]
+	if !ok {
+		for key, cf := range i.CustomFields {
+			if eq(key, field) {
+				f, ok = cf, true
+				break
+			}
+		}
+	}
+	return ok && eq(f.String(), value)
+}
+
+// SearchIssues returns all issues matching the query, sorted as requested
+// by the query.
+//
+// If the corpus is updated concurrently, the caller must hold its read lock.
+func (c *Corpus) SearchIssues(q Query) []*Issue {
+	var ret []*Issue
+	for _, i := range c.Issues {
+		if q.Match(i) {
+			ret = append(ret, i)
+		}
+	}
+	SortIssues(ret, q.Sort)
+	return ret
+}
+
+// SortIssues sorts the issues by key, priority, updated, created or points.
+// Issues are sorted by key if order is empty or unknown.
+func SortIssues(issues []*Issue, order string) {
+	byKey := func(a, b *Issue) bool { return a.IssueKey < b.IssueKey }
+	less := byKey
+	switch order {
+	case "priority":
+		less = func(a, b *Issue) bool {
+			if a.PriorityRank() != b.PriorityRank() {
+				return a.PriorityRank() < b.PriorityRank()
+			}
+			return byKey(a, b)
+		}
+	case "updated":
+		less = func(a, b *Issue) bool { return a.Updated.After(b.Updated) }
+	case "created":
+		less = func(a, b *Issue) bool { return a.Created.After(b.Created) }
+	case "points":
+		less = func(a, b *Issue) bool {
+			if a.StoryPoints != b.StoryPoints {
+				return a.StoryPoints > b.StoryPoints
+			}
+			return byKey(a, b)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return less(issues[i], issues[j]) })
+}
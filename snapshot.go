@@ -0,0 +1,328 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/urld/devdashboard/devdashpb"
+	"github.com/urld/devdashboard/reclog"
+)
+
+// A snapshot is a checkpoint of the mutation log. It holds the mutations
+// to build the corpus from scratch, as of a position in the log, in the
+// reclog format of the log files. Snapshots are named after that position,
+// "<log file>.<hex offset>.snapshot", such as
+// "devdashboard-2019-01-02.mutlog.00000000000004d2.snapshot", so the newest
+// snapshot comes last in lexical order.
+const snapshotSuffix = ".snapshot"
+
+func snapshotName(logFile string, off int64) string {
+	return fmt.Sprintf("%s.%016x%s", logFile, off, snapshotSuffix)
+}
+
+// parseSnapshotName returns the position in the log of the named snapshot.
+func parseSnapshotName(name string) (logFile string, off int64, ok bool) {
+	if !strings.HasPrefix(name, "devdashboard-") || !strings.HasSuffix(name, snapshotSuffix) {
+		return "", 0, false
+	}
+	s := strings.TrimSuffix(name, snapshotSuffix)
+	i := strings.LastIndexByte(s, '.')
+	if i < 0 || len(s)-i-1 != 16 {
+		return "", 0, false
+	}
+	off, err := strconv.ParseInt(s[i+1:], 16, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return s[:i], off, true
+}
+
+// SnapshotMutations returns the mutations which build the current state of
+// the corpus from scratch. The caller must hold the read lock.
+func (c *Corpus) SnapshotMutations() []*devdashpb.Mutation {
+	var ms []*devdashpb.Mutation
+
+	projects := make([]string, 0, len(c.Projects))
+	for id := range c.Projects {
+		projects = append(projects, id)
+	}
+	sort.Strings(projects)
+	for _, id := range projects {
+		var p *Project
+		ms = append(ms, &devdashpb.Mutation{Project: p.GenMutationDiff(c.Projects[id])})
+	}
+
+	releases := make([]string, 0, len(c.Releases))
+	for id := range c.Releases {
+		releases = append(releases, id)
+	}
+	sort.Strings(releases)
+	for _, id := range releases {
+		var r *Release
+		ms = append(ms, &devdashpb.Mutation{Release: r.GenMutationDiff(c.Releases[id])})
+	}
+
+	issues := make([]string, 0, len(c.Issues))
+	for id := range c.Issues {
+		issues = append(issues, id)
+	}
+	sort.Strings(issues)
+	for _, id := range issues {
+		for _, im := range issueSnapshot(c.Issues[id]) {
+			ms = append(ms, &devdashpb.Mutation{Issue: im})
+		}
+	}
+
+	sprints := make([]string, 0, len(c.Sprints))
+	for id := range c.Sprints {
+		sprints = append(sprints, id)
+	}
+	sort.Strings(sprints)
+	for _, id := range sprints {
+		var s *Sprint
+		ms = append(ms, &devdashpb.Mutation{Sprint: s.GenMutationDiff(c.Sprints[id])})
+	}
+
+	repos := make([]string, 0, len(c.GitRepos))
+	for url := range c.GitRepos {
+		repos = append(repos, url)
+	}
+	sort.Strings(repos)
+	for _, url := range repos {
+		var r *GitRepo
+		for _, gm := range r.GenMutationDiffs(c.GitRepos[url]) {
+			ms = append(ms, &devdashpb.Mutation{Git: gm})
+		}
+	}
+	return ms
+}
+
+// issueSnapshot returns the mutations to create the issue. The status
+// history is derived from the order of mutations, so each status change is
+// a separate mutation.
+func issueSnapshot(i *Issue) []*devdashpb.IssueMutation {
+	var empty *Issue
+	im := empty.GenMutationDiff(i)
+	hist := i.StatusHistory
+	if len(hist) == 0 {
+		return []*devdashpb.IssueMutation{im}
+	}
+	ret := []*devdashpb.IssueMutation{im}
+	im.Status = ""
+	for n, sc := range hist {
+		// untimed changes must not take the time of the issue
+		sm := im
+		if n > 0 || sc.Time.IsZero() {
+			sm = &devdashpb.IssueMutation{Id: i.ID}
+			ret = append(ret, sm)
+		}
+		sm.Status = sc.Status
+		sm.Updated = nil
+		if !sc.Time.IsZero() {
+			sm.Updated = pbTimestamp(sc.Time)
+		}
+	}
+	if !i.Updated.Equal(hist[len(hist)-1].Time) {
+		ret = append(ret, &devdashpb.IssueMutation{Id: i.ID, Updated: pbTimestamp(i.Updated)})
+	}
+	return ret
+}
+
+// WriteSnapshot writes a snapshot of all mutations logged so far and
+// removes older snapshots. GetMutations starts from the newest snapshot,
+// so loading the corpus does not replay the whole log anymore.
+//
+// The snapshot is built by a corpus of its own, so WriteSnapshot may be
+// called while mutations are logged.
+func (d *DiskMutationLogger) WriteSnapshot(ctx context.Context) (filename string, err error) {
+	src := NewDiskMutationLogger(d.directory)
+	c := new(Corpus)
+	if err := c.Initialize(ctx, src); err != nil {
+		return "", err
+	}
+	logFile, off := src.position()
+	if logFile == "" {
+		return "", errors.New("no mutations logged yet")
+	}
+	filename = d.path(snapshotName(logFile, off))
+	if _, err := os.Stat(filename); err == nil {
+		return filename, nil
+	}
+
+	f, err := ioutil.TempFile(d.directory, "tmp-snapshot-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	bw := bufio.NewWriter(f)
+	cw := &countingWriter{w: bw}
+	for _, m := range c.SnapshotMutations() {
+		data, err := proto.Marshal(m)
+		if err != nil {
+			return "", err
+		}
+		if err := reclog.WriteRecord(cw, cw.n, data); err != nil {
+			return "", err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return "", err
+	}
+	if err := f.Sync(); err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(f.Name(), filename); err != nil {
+		return "", err
+	}
+
+	old, err := d.snapshots()
+	if err != nil {
+		return filename, err
+	}
+	for _, name := range old {
+		if d.path(name) != filename {
+			if err := os.Remove(d.path(name)); err != nil {
+				return filename, err
+			}
+		}
+	}
+	return filename, nil
+}
+
+// position returns the log file and offset up to which mutations were
+// sent by GetMutations.
+func (d *DiskMutationLogger) position() (logFile string, off int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for path, o := range d.offsets {
+		if name := filepath.Base(path); name > logFile {
+			logFile, off = name, o
+		}
+	}
+	return logFile, off
+}
+
+// snapshots returns the names of all snapshots, the newest last.
+func (d *DiskMutationLogger) snapshots() ([]string, error) {
+	fis, err := ioutil.ReadDir(d.directory)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, fi := range fis {
+		if _, _, ok := parseSnapshotName(fi.Name()); ok && fi.Mode().IsRegular() {
+			names = append(names, fi.Name())
+		}
+	}
+	return names, nil
+}
+
+// sendSnapshot sends the mutations of the newest snapshot on the first
+// call and skips the logged mutations it includes. Broken snapshots are
+// ignored, so the whole log is replayed instead.
+func (d *DiskMutationLogger) sendSnapshot(ctx context.Context, ch chan<- MutationStreamEvent) (err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.offsets != nil {
+		return nil
+	}
+	d.offsets = make(map[string]int64)
+	snapshots, err := d.snapshots()
+	if err != nil || len(snapshots) == 0 {
+		return err
+	}
+	name := snapshots[len(snapshots)-1]
+	logFile, end, _ := parseSnapshotName(name)
+	if fi, err := os.Stat(d.path(logFile)); err != nil || fi.Size() < end {
+		// compressed log files are complete
+		if _, err := os.Stat(d.path(logFile + compressedSuffix)); err != nil {
+			log.Printf("ignoring snapshot %s: log file %s is missing or truncated", name, logFile)
+			return nil
+		}
+	}
+	// verify the whole snapshot before sending anything
+	err = reclog.ForeachFileRecord(d.path(name), func(off int64, hdr, rec []byte) error {
+		return proto.Unmarshal(rec, new(devdashpb.Mutation))
+	})
+	if err != nil {
+		log.Printf("ignoring snapshot: %v", err)
+		return nil
+	}
+
+	defer func() {
+		if err != nil {
+			// replay the whole log on the next call
+			d.offsets = nil
+		}
+	}()
+	err = reclog.ForeachFileRecord(d.path(name), func(off int64, hdr, rec []byte) error {
+		m := new(devdashpb.Mutation)
+		if err := proto.Unmarshal(rec, m); err != nil {
+			return err
+		}
+		select {
+		case ch <- MutationStreamEvent{Mutation: m}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fis, err := ioutil.ReadDir(d.directory)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		seg := segmentPath(fi.Name())
+		if !isLogFile(fi.Name()) || seg >= logFile {
+			continue
+		}
+		if seg != fi.Name() {
+			if d.sent == nil {
+				d.sent = make(map[string]bool)
+			}
+			d.sent[d.path(seg)] = true
+		} else {
+			d.offsets[d.path(seg)] = fi.Size()
+		}
+	}
+	d.offsets[d.path(logFile)] = end
+	return nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	d := NewDiskMutationLogger(dir)
+	day := func(n int) time.Time { return time.Date(2019, 1, n, 9, 0, 0, 0, time.UTC) }
+	for _, m := range []*devdashpb.Mutation{
+		{Project: &devdashpb.ProjectMutation{
+			Id:         "ABC",
+			Name:       "Alpha Bravo Charlie",
+			Milestones: []*devdashpb.TrackerMilestone{{Id: "m1", Project: "ABC", Name: "v1"}},
+		}},
+		{Issue: &devdashpb.IssueMutation{
+			Id: "i1", Project: "ABC", IssueKey: "ABC-1", Title: "Setup project",
+			Created: pbTimestamp(day(1)), Status: "New",
+			Milestones: []*devdashpb.TrackerMilestone{{Id: "m1"}},
+		}},
+		{Issue: &devdashpb.IssueMutation{Id: "i1", Status: "In Progress", Updated: pbTimestamp(day(2))}},
+		{Issue: &devdashpb.IssueMutation{Id: "i1", Status: "Done", Updated: pbTimestamp(day(3))}},
+		{Issue: &devdashpb.IssueMutation{Id: "i1", Body: "done", Updated: pbTimestamp(day(4))}},
+		{Sprint: &devdashpb.SprintMutation{
+			Id: "s1", Project: "ABC", Name: "Sprint 1", State: devdashpb.SprintState_ACTIVE,
+			Issues: []*devdashpb.SprintIssue{{Issue: "i1", Added: pbTimestamp(day(1))}},
+		}},
+		{Git: &devdashpb.GitMutation{Repo: "abc", Commit: testCommit("a1", "", "ABC-1: setup", 0)}},
+		{Git: &devdashpb.GitMutation{Repo: "abc", Refs: []*devdashpb.GitRef{{Ref: "refs/heads/master", Sha1: "a1"}}}},
+	} {
+		checkErr(t, d.Log(m))
+	}
+
+	filename, err := d.WriteSnapshot(context.Background())
+	checkErr(t, err)
+	logFile, off, ok := parseSnapshotName(filepath.Base(filename))
+	fi, err := os.Stat(d.filename())
+	checkErr(t, err)
+	if !ok || logFile != fi.Name() || off != fi.Size() {
+		t.Fatalf("snapshot %s should be positioned at the end of the log", filename)
+	}
+	checkErr(t, d.Log(&devdashpb.Mutation{Issue: &devdashpb.IssueMutation{Id: "i2", Project: "ABC", IssueKey: "ABC-2"}}))
+
+	// the snapshotted part of the log must not be read anymore:
+	data, err := ioutil.ReadFile(d.filename())
+	checkErr(t, err)
+	data[0] = 'X'
+	checkErr(t, ioutil.WriteFile(d.filename(), data, 0600))
+
+	c := new(Corpus)
+	checkErr(t, c.Initialize(context.Background(), NewDiskMutationLogger(dir)))
+	checkErr(t, c.Check())
+	i1 := c.Issues["i1"]
+	if i1 == nil || i1.Title != "Setup project" || i1.Body != "done" || !i1.Updated.Equal(day(4)) {
+		t.Fatalf("unexpected issue i1: %+v", i1)
+	}
+	want := []IssueStatusChange{{"New", day(1)}, {"In Progress", day(2)}, {"Done", day(3)}}
+	if len(i1.StatusHistory) != len(want) {
+		t.Fatalf("expected status history %v, got %v", want, i1.StatusHistory)
+	}
+	for n, sc := range i1.StatusHistory {
+		if sc.Status != want[n].Status || !sc.Time.Equal(want[n].Time) {
+			t.Errorf("expected status history %v, got %v", want, i1.StatusHistory)
+		}
+	}
+	if m := i1.Milestones["m1"]; m == nil || m.Name != "v1" || m.Project() != c.Projects["ABC"] {
+		t.Errorf("unexpected milestone %+v", m)
+	}
+	if gc := i1.Commits["a1"]; gc == nil || gc.DiffTree["a1.txt"] == nil {
+		t.Errorf("commit a1 should be linked to i1 with its diff tree, got %v", i1.Commits)
+	}
+	if head := c.GitRepos["abc"].DefaultHead(); head != "a1" {
+		t.Errorf("expected master at a1, got %q", head)
+	}
+	if s := c.Sprints["s1"]; s == nil || s.State != SprintActive || len(s.issues) != 1 {
+		t.Errorf("unexpected sprint %+v", s)
+	}
+	if c.Issues["i2"] == nil {
+		t.Errorf("issue i2 logged after the snapshot is missing")
+	}
+
+	// broken snapshots are ignored:
+	checkErr(t, ioutil.WriteFile(filename, []byte("REC@0+10=broken"), 0600))
+	c = new(Corpus)
+	if err := c.Initialize(context.Background(), NewDiskMutationLogger(dir)); err == nil {
+		t.Errorf("the corrupt log should be replayed if the snapshot is broken")
+	}
+}
+
+func TestSnapshotLabel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "devdashboard")
+	checkErr(t, err)
+	defer os.RemoveAll(dir)
+
+	d := NewDiskMutationLogger(dir)
+	checkErr(t, d.Log(&devdashpb.Mutation{Project: &devdashpb.ProjectMutation{
+		Id:     "ABC",
+		Labels: []*devdashpb.Label{{Id: "bug"}, {Id: "ui", Name: "UI"}},
+	}}))
+	_, err = d.WriteSnapshot(context.Background())
+	checkErr(t, err)
+
+	c := new(Corpus)
+	checkErr(t, c.Initialize(context.Background(), NewDiskMutationLogger(dir)))
+	p := c.Projects["ABC"]
+	if p == nil || len(p.Labels) != 2 || p.Labels["bug"] == nil || p.Labels["ui"] == nil || p.Labels["ui"].Name != "UI" {
+		t.Fatalf("expected labels bug and ui, got %v", p)
+	}
+	if l := p.Labels["bug"]; l.ID != "bug" {
+		t.Errorf("expected label bug, got %+v", l)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+// SprintState is the state of a sprint.
+type SprintState int
+
+const (
+	SprintFuture SprintState = iota
+	SprintActive
+	SprintClosed
+)
+
+func (s SprintState) String() string {
+	switch s {
+	case SprintActive:
+		return "active"
+	case SprintClosed:
+		return "closed"
+	}
+	return "future"
+}
+
+func (s SprintState) pb() devdashpb.SprintState {
+	switch s {
+	case SprintActive:
+		return devdashpb.SprintState_ACTIVE
+	case SprintClosed:
+		return devdashpb.SprintState_CLOSED
+	}
+	return devdashpb.SprintState_FUTURE
+}
+
+// Sprint is a time boxed iteration of a project or tracker board.
+type Sprint struct {
+	c *Corpus
+	p *Project
+
+	ID    string
+	Board string // tracker specific board, if any
+
+	Name string
+	Goal string
+
+	StartDate    time.Time
+	EndDate      time.Time
+	CompleteDate time.Time
+	State        SprintState
+
+	issues map[string]time.Time // issue ID -> time the issue was added
+}
+
+// Project returns the project the sprint belongs to, or nil if the sprint
+// is only scoped by a board.
+func (s *Sprint) Project() *Project {
+	return s.p
+}
+
+// End returns the time the sprint was completed, or its planned end date if
+// it is not completed yet.
+func (s *Sprint) End() time.Time {
+	if !s.CompleteDate.IsZero() {
+		return s.CompleteDate
+	}
+	return s.EndDate
+}
+
+// Issues returns all issues of the sprint sorted by issue key.
+func (s *Sprint) Issues() []*Issue {
+	return s.filterIssues(func(*Issue) bool { return true })
+}
+
+// Added returns the time the issue was added to the sprint, or the zero
+// time if the time is not known.
+func (s *Sprint) Added(i *Issue) time.Time {
+	return s.issues[i.ID]
+}
+
+// HasIssue reports whether the issue is part of the sprint.
+func (s *Sprint) HasIssue(i *Issue) bool {
+	_, ok := s.issues[i.ID]
+	return ok
+}
+
+// Committed returns the issues which were part of the sprint when it
+// started. Issues without a known added time count as committed.
+func (s *Sprint) Committed() []*Issue {
+	return s.filterIssues(s.isCommitted)
+}
+
+// AddedAfterStart returns the issues which were added to the sprint after it
+// started.
+func (s *Sprint) AddedAfterStart() []*Issue {
+	return s.filterIssues(func(i *Issue) bool { return !s.isCommitted(i) })
+}
+
+// Completed returns the issues which were closed before the end of the
+// sprint.
+func (s *Sprint) Completed() []*Issue {
+	return s.filterIssues(s.isCompleted)
+}
+
+// Remaining returns the issues which were not closed before the end of the
+// sprint. Remaining issues of a closed sprint carry over to a later sprint.
+func (s *Sprint) Remaining() []*Issue {
+	return s.filterIssues(func(i *Issue) bool { return !s.isCompleted(i) })
+}
+
+// CarriedOver returns the issues which remained open in an earlier sprint
+// and were carried over into this sprint.
+func (s *Sprint) CarriedOver() []*Issue {
+	return s.filterIssues(func(i *Issue) bool {
+		for _, prev := range i.Sprints() {
+			if prev != s && prev.StartDate.Before(s.StartDate) && !prev.isCompleted(i) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (s *Sprint) isCommitted(i *Issue) bool {
+	added := s.issues[i.ID]
+	return added.IsZero() || !added.After(s.StartDate)
+}
+
+func (s *Sprint) isCompleted(i *Issue) bool {
+	if !i.Closed {
+		return false
+	}
+	end := s.End()
+	return i.ClosedAt.IsZero() || end.IsZero() || !i.ClosedAt.After(end)
+}
+
+func (s *Sprint) filterIssues(keep func(*Issue) bool) []*Issue {
+	var ret []*Issue
+	for id := range s.issues {
+		if i, ok := s.c.Issues[id]; ok && keep(i) {
+			ret = append(ret, i)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].IssueKey < ret[j].IssueKey })
+	return ret
+}
+
+// Sprints returns all sprints the issue is part of, sorted by start date.
+func (i *Issue) Sprints() []*Sprint {
+	var ret []*Sprint
+	for _, s := range i.c.Sprints {
+		if s.HasIssue(i) {
+			ret = append(ret, s)
+		}
+	}
+	SortSprints(ret)
+	return ret
+}
+
+// SortSprints sorts sprints by start date.
+func SortSprints(sprints []*Sprint) {
+	sort.Slice(sprints, func(i, j int) bool {
+		a, b := sprints[i], sprints[j]
+		if !a.StartDate.Equal(b.StartDate) {
+			return a.StartDate.Before(b.StartDate)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// StoryPoints returns the sum of the story points of all issues.
+func StoryPoints(issues []*Issue) float64 {
+	var sum float64
+	for _, i := range issues {
+		sum += i.StoryPoints
+	}
+	return sum
+}
+
+func (c *Corpus) processSprintMutation(sm *devdashpb.SprintMutation) {
+	s, ok := c.Sprints[sm.Id]
+	if !ok {
+		// new sprint
+		s = &Sprint{
+			c:      c,
+			ID:     sm.Id,
+			issues: make(map[string]time.Time),
+		}
+		c.Sprints[sm.Id] = s
+	}
+	if sm.Project != "" {
+		s.p = c.getOrCreateProject(sm.Project)
+	}
+	if sm.Board != "" {
+		s.Board = sm.Board
+	}
+	if sm.Name != "" {
+		s.Name = sm.Name
+	}
+	if sm.Goal != "" {
+		s.Goal = sm.Goal
+	}
+	if sm.StartDate != nil {
+		s.StartDate = pbTime(sm.StartDate)
+	}
+	if sm.EndDate != nil {
+		s.EndDate = pbTime(sm.EndDate)
+	}
+	if sm.CompleteDate != nil {
+		s.CompleteDate = pbTime(sm.CompleteDate)
+	}
+	switch sm.State {
+	case devdashpb.SprintState_FUTURE:
+		s.State = SprintFuture
+	case devdashpb.SprintState_ACTIVE:
+		s.State = SprintActive
+	case devdashpb.SprintState_CLOSED:
+		s.State = SprintClosed
+	}
+	for _, sim := range sm.Issues {
+		var added time.Time
+		if sim.Added != nil {
+			added = pbTime(sim.Added)
+		}
+		s.issues[sim.Issue] = added
+	}
+	for _, id := range sm.DeletedIssues {
+		delete(s.issues, id)
+	}
+}
+
+var emptySprint = &Sprint{}
+
+func (a *Sprint) GenMutationDiff(b *Sprint) *devdashpb.SprintMutation {
+	var ret *devdashpb.SprintMutation // lazily initialized by diff
+	diff := func() *devdashpb.SprintMutation {
+		if ret == nil {
+			ret = &devdashpb.SprintMutation{Id: b.ID}
+		}
+		return ret
+	}
+	if a == nil {
+		a = emptySprint
+	}
+	if b.p != nil && (a.p == nil || a.p.ID != b.p.ID) {
+		diff().Project = b.p.ID
+	}
+	if a.Board != b.Board {
+		diff().Board = b.Board
+	}
+	if a.Name != b.Name {
+		diff().Name = b.Name
+	}
+	if a.Goal != b.Goal {
+		diff().Goal = b.Goal
+	}
+	if a.StartDate != b.StartDate {
+		diff().StartDate = pbTimestamp(b.StartDate)
+	}
+	if a.EndDate != b.EndDate {
+		diff().EndDate = pbTimestamp(b.EndDate)
+	}
+	if a.CompleteDate != b.CompleteDate {
+		diff().CompleteDate = pbTimestamp(b.CompleteDate)
+	}
+	if a == emptySprint || a.State != b.State {
+		diff().State = b.State.pb()
+	}
+	for id := range a.issues {
+		if _, ok := b.issues[id]; !ok {
+			diff().DeletedIssues = append(diff().DeletedIssues, id)
+		}
+	}
+	for id, added := range b.issues {
+		if prev, ok := a.issues[id]; ok && prev == added {
+			continue
+		}
+		sim := &devdashpb.SprintIssue{Issue: id}
+		if !added.IsZero() {
+			sim.Added = pbTimestamp(added)
+		}
+		diff().Issues = append(diff().Issues, sim)
+	}
+	return ret
+}
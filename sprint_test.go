@@ -0,0 +1,100 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+package devdashboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/urld/devdashboard/devdashpb"
+)
+
+func TestSprintMutation(t *testing.T) {
+	l := newLogger()
+	c := &Corpus{}
+
+	day := func(d int) time.Time { return time.Date(2019, 1, d, 9, 0, 0, 0, time.UTC) }
+	for _, im := range []*devdashpb.IssueMutation{
+		{Id: "i1", Project: "ABC", IssueKey: "ABC-1", StoryPoints: &devdashpb.DoubleChange{Val: 3},
+			Closed: pbBool(true), ClosedAt: pbTimestamp(day(10))},
+		{Id: "i2", Project: "ABC", IssueKey: "ABC-2", StoryPoints: &devdashpb.DoubleChange{Val: 5}},
+		{Id: "i3", Project: "ABC", IssueKey: "ABC-3", StoryPoints: &devdashpb.DoubleChange{Val: 1},
+			Closed: pbBool(true), ClosedAt: pbTimestamp(day(20))},
+	} {
+		checkErr(t, l.Log(&devdashpb.Mutation{Issue: im}))
+	}
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Sprint: &devdashpb.SprintMutation{
+			Id:           "s1",
+			Project:      "ABC",
+			Name:         "Sprint 1",
+			StartDate:    pbTimestamp(day(1)),
+			EndDate:      pbTimestamp(day(14)),
+			CompleteDate: pbTimestamp(day(14)),
+			State:        devdashpb.SprintState_CLOSED,
+			Issues: []*devdashpb.SprintIssue{
+				{Issue: "i1"},
+				{Issue: "i2", Added: pbTimestamp(day(1))},
+				{Issue: "i3", Added: pbTimestamp(day(3))},
+			},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Sprint: &devdashpb.SprintMutation{
+			Id:        "s2",
+			Project:   "ABC",
+			Name:      "Sprint 2",
+			StartDate: pbTimestamp(day(15)),
+			EndDate:   pbTimestamp(day(28)),
+			State:     devdashpb.SprintState_ACTIVE,
+			Issues:    []*devdashpb.SprintIssue{{Issue: "i2"}, {Issue: "i3"}, {Issue: "i4"}},
+		},
+	}))
+	checkErr(t, l.Log(&devdashpb.Mutation{
+		Sprint: &devdashpb.SprintMutation{Id: "s2", DeletedIssues: []string{"i4"}},
+	}))
+
+	l.end()
+	checkErr(t, c.Initialize(context.Background(), l))
+
+	s1, s2 := c.Sprints["s1"], c.Sprints["s2"]
+	if s1.State != SprintClosed || s2.State != SprintActive {
+		t.Errorf("unexpected sprint states %s and %s", s1.State, s2.State)
+	}
+	if n := len(s1.Committed()); n != 2 {
+		t.Errorf("Sprint s1 should have 2 committed issues, got %d", n)
+	}
+	if added := s1.AddedAfterStart(); len(added) != 1 || added[0].ID != "i3" {
+		t.Errorf("Issue i3 should have been added after start, got %v", added)
+	}
+	if p := StoryPoints(s1.Completed()); p != 3 {
+		t.Errorf("Sprint s1 should have completed 3 points, got %v", p)
+	}
+	if carried := s2.CarriedOver(); len(carried) != 2 {
+		t.Errorf("Sprint s2 should have 2 carried over issues, got %v", carried)
+	}
+	if n := len(s2.Issues()); n != 2 {
+		t.Errorf("Sprint s2 should have 2 issues, got %d", n)
+	}
+	if sprints := c.Issues["i2"].Sprints(); len(sprints) != 2 || sprints[0] != s1 {
+		t.Errorf("unexpected sprints of issue i2: %v", sprints)
+	}
+
+	m := (*Sprint)(nil).GenMutationDiff(s1)
+	if m.Name != "Sprint 1" || m.State != devdashpb.SprintState_CLOSED || len(m.Issues) != 3 {
+		t.Errorf("unexpected mutation diff %v", m)
+	}
+
+	// a corpus with the same sprint has no diff, although its projects are
+	// different objects:
+	l = newLogger()
+	checkErr(t, l.Log(&devdashpb.Mutation{Sprint: m}))
+	l.end()
+	c2 := &Corpus{}
+	checkErr(t, c2.Initialize(context.Background(), l))
+	if m := c2.Sprints["s1"].GenMutationDiff(s1); m != nil {
+		t.Errorf("expected no mutation diff, got %v", m)
+	}
+}
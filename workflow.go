@@ -0,0 +1,93 @@
+// Copyright 2018 David Url.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package devdashboard
+
+import "github.com/urld/devdashboard/devdashpb"
+
+// StatusCategory groups issue statuses by their meaning in the development
+// process.
+type StatusCategory int
+
+const (
+	StatusUnknown StatusCategory = iota
+	StatusTodo
+	StatusInProgress
+	StatusDone
+)
+
+// StatusCategories lists all known categories in workflow order.
+var StatusCategories = []StatusCategory{StatusTodo, StatusInProgress, StatusDone}
+
+func (sc StatusCategory) String() string {
+	switch sc {
+	case StatusTodo:
+		return "To Do"
+	case StatusInProgress:
+		return "In Progress"
+	case StatusDone:
+		return "Done"
+	}
+	return "Unknown"
+}
+
+// WorkflowStatus is a status of a project workflow.
+type WorkflowStatus struct {
+	Name     string
+	Category StatusCategory
+}
+
+// StatusCategory returns the category of the named status in the project's
+// workflow, or StatusUnknown if the workflow does not define the status.
+func (p *Project) StatusCategory(status string) StatusCategory {
+	for _, ws := range p.Workflow {
+		if ws.Name == status {
+			return ws.Category
+		}
+	}
+	return StatusUnknown
+}
+
+// StatusCategory returns the category of the issue's status as defined by
+// the workflow of its project. Closed issues are always done. Issues with a
+// status which is not part of the workflow are considered to do.
+func (i *Issue) StatusCategory() StatusCategory {
+	if i.Closed {
+		return StatusDone
+	}
+	if i.p != nil {
+		if sc := i.p.StatusCategory(i.Status); sc != StatusUnknown {
+			return sc
+		}
+	}
+	return StatusTodo
+}
+
+func (c *Corpus) processWorkflowMutation(p *Project, wm *devdashpb.Workflow) {
+	p.Workflow = make([]WorkflowStatus, 0, len(wm.Statuses))
+	for _, sm := range wm.Statuses {
+		p.Workflow = append(p.Workflow, WorkflowStatus{
+			Name:     sm.Name,
+			Category: StatusCategory(sm.Category),
+		})
+	}
+}
+
+func genWorkflowDiff(a, b []WorkflowStatus) *devdashpb.Workflow {
+	equal := len(a) == len(b)
+	for i := 0; equal && i < len(a); i++ {
+		equal = a[i] == b[i]
+	}
+	if equal {
+		return nil
+	}
+	wm := &devdashpb.Workflow{}
+	for _, ws := range b {
+		wm.Statuses = append(wm.Statuses, &devdashpb.WorkflowStatus{
+			Name:     ws.Name,
+			Category: devdashpb.StatusCategory(ws.Category),
+		})
+	}
+	return wm
+}